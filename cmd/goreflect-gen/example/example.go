@@ -0,0 +1,45 @@
+// Package example has types with walkers generated by goreflect-gen, to test that they are equivalent to
+// goreflect.ValueDepthFirstWalker.
+package example
+
+import (
+	"image"
+	"time"
+)
+
+//go:generate go run github.com/bantling/goreflect/cmd/goreflect-gen -types Order,Celsius,Shape
+
+// Celsius is a named scalar type
+type Celsius float64
+
+// Item has unexported fields
+type Item struct {
+	Name  string
+	Price *int
+	tags  []string
+}
+
+// Shape is an interface type
+type Shape interface {
+	Area() float64
+}
+
+// Order has a field of each kind
+type Order struct {
+	ID     int
+	Items  []*Item
+	Index  map[string]Item
+	Temps  [2]Celsius
+	Any    interface{}
+	Shape  Shape
+	Next   *Order
+	Done   chan bool
+	Notify func(Order) error
+	When   time.Time
+	Corner image.Point
+	Meta   struct {
+		Labels map[Celsius][]byte
+		Empty  struct{}
+	}
+	Item
+}
@@ -0,0 +1,87 @@
+package example
+
+import (
+	"errors"
+	"image"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bantling/goreflect"
+	"github.com/stretchr/testify/assert"
+)
+
+type square float64
+
+func (s square) Area() float64 {
+	return float64(s * s)
+}
+
+func newOrder() Order {
+	var (
+		price = 5
+		item  = &Item{Name: "foo", Price: &price, tags: []string{"a", "b"}}
+		order = Order{
+			ID:     1,
+			Items:  []*Item{item, nil, item},
+			Index:  map[string]Item{"foo": *item, "bar": {Name: "bar"}},
+			Temps:  [2]Celsius{20.5, 21},
+			Any:    []interface{}{1, "two", nil},
+			Shape:  square(2),
+			Done:   make(chan bool),
+			Notify: func(Order) error { return nil },
+			When:   time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC),
+			Corner: image.Point{X: 3, Y: 4},
+			Item:   Item{Name: "embedded"},
+		}
+	)
+
+	order.Meta.Labels = map[Celsius][]byte{1.5: []byte("x"), -1: nil}
+	order.Next = &Order{ID: 2}
+
+	return order
+}
+
+func TestGeneratedWalkers(t *testing.T) {
+	var (
+		w     = goreflect.NewValueDepthFirstWalker()
+		order = newOrder()
+	)
+
+	assert.Nil(t, goreflect.CompareWalks(w, order, func(w goreflect.ValueDepthFirstWalker) { WalkOrder(w, order) }))
+	assert.Nil(t, goreflect.CompareWalks(w, Order{}, func(w goreflect.ValueDepthFirstWalker) { WalkOrder(w, Order{}) }))
+	assert.Nil(t, goreflect.CompareWalks(w, Celsius(1.5), func(w goreflect.ValueDepthFirstWalker) { WalkCelsius(w, 1.5) }))
+	assert.Nil(t, goreflect.CompareWalks(w, square(3), func(w goreflect.ValueDepthFirstWalker) { WalkShape(w, square(3)) }))
+	assert.Nil(t, goreflect.CompareWalks(w, nil, func(w goreflect.ValueDepthFirstWalker) { WalkShape(w, nil) }))
+
+	// A NaN key is walked with its value, in the same order
+	order.Meta.Labels[Celsius(math.NaN())] = []byte("n")
+	assert.Nil(t, goreflect.CompareWalks(w, order, func(w goreflect.ValueDepthFirstWalker) { WalkOrder(w, order) }))
+
+	// Back and shared references are handled the same way
+	order.Next = &order
+	w.WithBackReferenceMode(goreflect.BackReferenceNotify)
+	assert.Nil(t, goreflect.CompareWalks(w, order, func(w goreflect.ValueDepthFirstWalker) { WalkOrder(w, order) }))
+
+	w.WithSharedReferences()
+	assert.Nil(t, goreflect.CompareWalks(w, order, func(w goreflect.ValueDepthFirstWalker) { WalkOrder(w, order) }))
+
+	// A difference is described
+	err := goreflect.CompareWalks(w, Celsius(1.5), func(w goreflect.ValueDepthFirstWalker) { WalkCelsius(w, 2.5) })
+	assert.Equal(
+		t,
+		errors.New(`goreflect.CompareWalks: event 0 differs: the walker visited {Method:VisitFloat64 Path:[] Args:[{Kind:float64 Type:float64 Text:1.5 Nil:false Len:0 Field:<nil> Path:[]}]}, the generated walker visited {Method:VisitFloat64 Path:[] Args:[{Kind:float64 Type:float64 Text:2.5 Nil:false Len:0 Field:<nil> Path:[]}]}`),
+		err,
+	)
+
+	// Limits are not supported
+	func() {
+		defer func() {
+			assert.Equal(t, errors.New("goreflect.NewGeneratedWalker: generated walkers do not support struct field options or limits"), recover())
+		}()
+
+		w.WithMaxDepth(2)
+		WalkOrder(w, order)
+		assert.Fail(t, "Must panic")
+	}()
+}
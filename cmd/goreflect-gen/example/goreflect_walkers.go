@@ -0,0 +1,727 @@
+// Code generated by goreflect-gen -types Order,Celsius,Shape; DO NOT EDIT.
+
+package example
+
+import (
+	"image"
+	"reflect"
+	"sort"
+
+	"github.com/bantling/goreflect"
+)
+
+// WalkOrder walks x in the same way as w.Walk(x), calling the same visitor methods in the same order
+func WalkOrder(w goreflect.ValueDepthFirstWalker, x Order) {
+	g := goreflect.NewGeneratedWalker(w)
+	goreflectWalk1(g, x)
+}
+
+// WalkCelsius walks x in the same way as w.Walk(x), calling the same visitor methods in the same order
+func WalkCelsius(w goreflect.ValueDepthFirstWalker, x Celsius) {
+	g := goreflect.NewGeneratedWalker(w)
+	g.Visitor().VisitFloat64(float64(x))
+}
+
+// WalkShape walks x in the same way as w.Walk(x), calling the same visitor methods in the same order
+func WalkShape(w goreflect.ValueDepthFirstWalker, x Shape) {
+	g := goreflect.NewGeneratedWalker(w)
+	g.Dispatch(reflect.ValueOf(x))
+}
+
+// goreflectWalk1 walks values of type Order
+func goreflectWalk1(g *goreflect.GeneratedWalker, x Order) {
+	v, n := reflect.ValueOf(x), 13
+	if g.PreStruct(n, v) {
+		{
+			sf, sv := goreflectFields1[0], reflect.ValueOf(x.ID)
+
+			g.Visitor().VisitPreStructFieldValue(n, 0, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 0, Name: sf.Name})
+			g.Visitor().VisitInt(int(x.ID))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 0, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[1], reflect.ValueOf(x.Items)
+
+			g.Visitor().VisitPreStructFieldValue(n, 1, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 1, Name: sf.Name})
+			goreflectWalk2(g, x.Items)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 1, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[2], reflect.ValueOf(x.Index)
+
+			g.Visitor().VisitPreStructFieldValue(n, 2, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 2, Name: sf.Name})
+			goreflectWalk3(g, x.Index)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 2, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[3], reflect.ValueOf(x.Temps)
+
+			g.Visitor().VisitPreStructFieldValue(n, 3, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 3, Name: sf.Name})
+			goreflectWalk4(g, x.Temps)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 3, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[4], reflect.ValueOf(&x.Any).Elem()
+
+			g.Visitor().VisitPreStructFieldValue(n, 4, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 4, Name: sf.Name})
+			g.Dispatch(reflect.ValueOf(&x.Any).Elem())
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 4, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[5], reflect.ValueOf(&x.Shape).Elem()
+
+			g.Visitor().VisitPreStructFieldValue(n, 5, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 5, Name: sf.Name})
+			g.Dispatch(reflect.ValueOf(&x.Shape).Elem())
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 5, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[6], reflect.ValueOf(x.Next)
+
+			g.Visitor().VisitPreStructFieldValue(n, 6, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 6, Name: sf.Name})
+			goreflectWalk5(g, x.Next)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 6, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[7], reflect.ValueOf(x.Done)
+
+			g.Visitor().VisitPreStructFieldValue(n, 7, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 7, Name: sf.Name})
+			g.Visitor().VisitChan(reflect.ValueOf(x.Done))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 7, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[8], reflect.ValueOf(x.Notify)
+
+			g.Visitor().VisitPreStructFieldValue(n, 8, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 8, Name: sf.Name})
+			g.Visitor().VisitFunc(reflect.ValueOf(x.Notify))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 8, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[9], reflect.ValueOf(x.When)
+
+			g.Visitor().VisitPreStructFieldValue(n, 9, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 9, Name: sf.Name})
+			g.Dispatch(reflect.ValueOf(x.When))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 9, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[10], reflect.ValueOf(x.Corner)
+
+			g.Visitor().VisitPreStructFieldValue(n, 10, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 10, Name: sf.Name})
+			goreflectWalk6(g, x.Corner)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 10, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[11], reflect.ValueOf(x.Meta)
+
+			g.Visitor().VisitPreStructFieldValue(n, 11, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 11, Name: sf.Name})
+			goreflectWalk7(g, x.Meta)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 11, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields1[12], reflect.ValueOf(x.Item)
+
+			g.Visitor().VisitPreStructFieldValue(n, 12, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 12, Name: sf.Name})
+			goreflectWalk8(g, x.Item)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 12, sf, sv)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStruct(n, v)
+}
+
+// goreflectFields1 are the fields of type Order
+var goreflectFields1 = goreflect.GeneratedStructFields(reflect.TypeOf((*Order)(nil)).Elem())
+
+// goreflectWalk2 walks values of type []*Item
+func goreflectWalk2(g *goreflect.GeneratedWalker, x []*Item) {
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	n := len(x)
+	if g.PreSlice(n, v) {
+		for i := 0; i < n; i++ {
+			e := reflect.ValueOf(x[i])
+
+			g.Visitor().VisitPreSliceIndex(n, i, e)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+			goreflectWalk9(g, x[i])
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostSliceIndex(n, i, e)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostSlice(n, v)
+	g.Leave(v)
+}
+
+// goreflectWalk3 walks values of type map[string]Item
+func goreflectWalk3(g *goreflect.GeneratedWalker, x map[string]Item) {
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	n := len(x)
+	if g.PreMap(n, v) {
+		entries := make([]struct {
+			k string
+			v Item
+		}, 0, n)
+		for k, mv := range x {
+			entries = append(entries, struct {
+				k string
+				v Item
+			}{k, mv})
+		}
+
+		if less := g.MapKeyLess(); less != nil {
+			sort.SliceStable(entries, func(a, b int) bool {
+				return less(reflect.ValueOf(entries[a].k), reflect.ValueOf(entries[b].k))
+			})
+		}
+
+		for i, e := range entries {
+			k, mv := e.k, e.v
+			mk, me := reflect.ValueOf(k), reflect.ValueOf(mv)
+
+			g.Visitor().VisitPreMapKeyValue(n, i, mk, me)
+
+			g.Visitor().VisitPreMapKey(n, i, mk)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapKey, Index: i, Key: mk})
+			g.Visitor().VisitString(string(k))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostMapKey(n, i, mk)
+
+			g.Visitor().VisitPreMapValue(n, i, me)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapValue, Index: i, Key: mk})
+			goreflectWalk8(g, mv)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostMapValue(n, i, me)
+
+			g.Visitor().VisitPostMapKeyValue(n, i, mk, me)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostMap(n, v)
+	g.Leave(v)
+}
+
+// goreflectWalk4 walks values of type [2]Celsius
+func goreflectWalk4(g *goreflect.GeneratedWalker, x [2]Celsius) {
+	v, n := reflect.ValueOf(x), len(x)
+	if g.PreArray(n, v) {
+		for i := 0; i < n; i++ {
+			e := reflect.ValueOf(x[i])
+
+			g.Visitor().VisitPreArrayIndex(n, i, e)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+			g.Visitor().VisitFloat64(float64(x[i]))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostArrayIndex(n, i, e)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostArray(n, v)
+}
+
+// goreflectWalk5 walks values of type *Order
+func goreflectWalk5(g *goreflect.GeneratedWalker, x *Order) {
+	if x == nil {
+		g.Visitor().VisitNil(reflect.ValueOf(x))
+		return
+	}
+
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	if g.PrePtr(v) {
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathDeref})
+		goreflectWalk1(g, *x)
+		g.Pop()
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostPtr(v)
+	g.Leave(v)
+}
+
+// goreflectWalk6 walks values of type image.Point
+func goreflectWalk6(g *goreflect.GeneratedWalker, x image.Point) {
+	v, n := reflect.ValueOf(x), 2
+	if g.PreStruct(n, v) {
+		{
+			sf, sv := goreflectFields6[0], reflect.ValueOf(x.X)
+
+			g.Visitor().VisitPreStructFieldValue(n, 0, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 0, Name: sf.Name})
+			g.Visitor().VisitInt(int(x.X))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 0, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields6[1], reflect.ValueOf(x.Y)
+
+			g.Visitor().VisitPreStructFieldValue(n, 1, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 1, Name: sf.Name})
+			g.Visitor().VisitInt(int(x.Y))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 1, sf, sv)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStruct(n, v)
+}
+
+// goreflectFields6 are the fields of type image.Point
+var goreflectFields6 = goreflect.GeneratedStructFields(reflect.TypeOf((*image.Point)(nil)).Elem())
+
+// goreflectWalk7 walks values of type struct{Labels map[Celsius][]byte; Empty struct{}}
+func goreflectWalk7(g *goreflect.GeneratedWalker, x struct {
+	Labels map[Celsius][]byte
+	Empty  struct{}
+}) {
+	v, n := reflect.ValueOf(x), 2
+	if g.PreStruct(n, v) {
+		{
+			sf, sv := goreflectFields7[0], reflect.ValueOf(x.Labels)
+
+			g.Visitor().VisitPreStructFieldValue(n, 0, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 0, Name: sf.Name})
+			goreflectWalk10(g, x.Labels)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 0, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields7[1], reflect.ValueOf(x.Empty)
+
+			g.Visitor().VisitPreStructFieldValue(n, 1, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 1, Name: sf.Name})
+			goreflectWalk11(g, x.Empty)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 1, sf, sv)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStruct(n, v)
+}
+
+// goreflectFields7 are the fields of type struct{Labels map[Celsius][]byte; Empty struct{}}
+var goreflectFields7 = goreflect.GeneratedStructFields(reflect.TypeOf((*struct {
+	Labels map[Celsius][]byte
+	Empty  struct{}
+})(nil)).Elem())
+
+// goreflectWalk8 walks values of type Item
+func goreflectWalk8(g *goreflect.GeneratedWalker, x Item) {
+	v, n := reflect.ValueOf(x), 3
+	if g.PreStruct(n, v) {
+		{
+			sf, sv := goreflectFields8[0], reflect.ValueOf(x.Name)
+
+			g.Visitor().VisitPreStructFieldValue(n, 0, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 0, Name: sf.Name})
+			g.Visitor().VisitString(string(x.Name))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 0, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields8[1], reflect.ValueOf(x.Price)
+
+			g.Visitor().VisitPreStructFieldValue(n, 1, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 1, Name: sf.Name})
+			goreflectWalk12(g, x.Price)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 1, sf, sv)
+		}
+		{
+			sf, sv := goreflectFields8[2], reflect.ValueOf(x.tags)
+
+			g.Visitor().VisitPreStructFieldValue(n, 2, sf, sv)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: 2, Name: sf.Name})
+			goreflectWalk13(g, x.tags)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostStructFieldValue(n, 2, sf, sv)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStruct(n, v)
+}
+
+// goreflectFields8 are the fields of type Item
+var goreflectFields8 = goreflect.GeneratedStructFields(reflect.TypeOf((*Item)(nil)).Elem())
+
+// goreflectWalk9 walks values of type *Item
+func goreflectWalk9(g *goreflect.GeneratedWalker, x *Item) {
+	if x == nil {
+		g.Visitor().VisitNil(reflect.ValueOf(x))
+		return
+	}
+
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	if g.PrePtr(v) {
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathDeref})
+		goreflectWalk8(g, *x)
+		g.Pop()
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostPtr(v)
+	g.Leave(v)
+}
+
+// goreflectWalk10 walks values of type map[Celsius][]byte
+func goreflectWalk10(g *goreflect.GeneratedWalker, x map[Celsius][]byte) {
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	n := len(x)
+	if g.PreMap(n, v) {
+		entries := make([]struct {
+			k Celsius
+			v []byte
+		}, 0, n)
+		for k, mv := range x {
+			entries = append(entries, struct {
+				k Celsius
+				v []byte
+			}{k, mv})
+		}
+
+		if less := g.MapKeyLess(); less != nil {
+			sort.SliceStable(entries, func(a, b int) bool {
+				return less(reflect.ValueOf(entries[a].k), reflect.ValueOf(entries[b].k))
+			})
+		}
+
+		for i, e := range entries {
+			k, mv := e.k, e.v
+			mk, me := reflect.ValueOf(k), reflect.ValueOf(mv)
+
+			g.Visitor().VisitPreMapKeyValue(n, i, mk, me)
+
+			g.Visitor().VisitPreMapKey(n, i, mk)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapKey, Index: i, Key: mk})
+			g.Visitor().VisitFloat64(float64(k))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostMapKey(n, i, mk)
+
+			g.Visitor().VisitPreMapValue(n, i, me)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapValue, Index: i, Key: mk})
+			goreflectWalk14(g, mv)
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostMapValue(n, i, me)
+
+			g.Visitor().VisitPostMapKeyValue(n, i, mk, me)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostMap(n, v)
+	g.Leave(v)
+}
+
+// goreflectWalk11 walks values of type struct{}
+func goreflectWalk11(g *goreflect.GeneratedWalker, x struct{}) {
+	v, n := reflect.ValueOf(x), 0
+	g.PreStruct(n, v)
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStruct(n, v)
+}
+
+// goreflectWalk12 walks values of type *int
+func goreflectWalk12(g *goreflect.GeneratedWalker, x *int) {
+	if x == nil {
+		g.Visitor().VisitNil(reflect.ValueOf(x))
+		return
+	}
+
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	if g.PrePtr(v) {
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathDeref})
+		g.Visitor().VisitInt(int(*x))
+		g.Pop()
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostPtr(v)
+	g.Leave(v)
+}
+
+// goreflectWalk13 walks values of type []string
+func goreflectWalk13(g *goreflect.GeneratedWalker, x []string) {
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	n := len(x)
+	if g.PreSlice(n, v) {
+		for i := 0; i < n; i++ {
+			e := reflect.ValueOf(x[i])
+
+			g.Visitor().VisitPreSliceIndex(n, i, e)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+			g.Visitor().VisitString(string(x[i]))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostSliceIndex(n, i, e)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostSlice(n, v)
+	g.Leave(v)
+}
+
+// goreflectWalk14 walks values of type []byte
+func goreflectWalk14(g *goreflect.GeneratedWalker, x []byte) {
+	v := reflect.ValueOf(x)
+	if !g.Enter(v) {
+		return
+	}
+
+	n := len(x)
+	if g.PreSlice(n, v) {
+		for i := 0; i < n; i++ {
+			e := reflect.ValueOf(x[i])
+
+			g.Visitor().VisitPreSliceIndex(n, i, e)
+			g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+			g.Visitor().VisitUint8(uint8(x[i]))
+			g.Pop()
+
+			if g.Stopped() {
+				return
+			}
+
+			g.Visitor().VisitPostSliceIndex(n, i, e)
+		}
+	}
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostSlice(n, v)
+	g.Leave(v)
+}
@@ -0,0 +1,579 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goreflectPath is the import path of goreflect
+const goreflectPath = "github.com/bantling/goreflect"
+
+// visitMethods are the ValueVisitor methods of the basic kinds that are visited as Go values
+var visitMethods = map[types.BasicKind]string{
+	types.Bool:       "VisitBool",
+	types.Int:        "VisitInt",
+	types.Int8:       "VisitInt8",
+	types.Int16:      "VisitInt16",
+	types.Int32:      "VisitInt32",
+	types.Int64:      "VisitInt64",
+	types.Uint:       "VisitUint",
+	types.Uint8:      "VisitUint8",
+	types.Uint16:     "VisitUint16",
+	types.Uint32:     "VisitUint32",
+	types.Uint64:     "VisitUint64",
+	types.Float32:    "VisitFloat32",
+	types.Float64:    "VisitFloat64",
+	types.Complex64:  "VisitComplex64",
+	types.Complex128: "VisitComplex128",
+	types.String:     "VisitString",
+}
+
+// walkerFunc is a generated func that walks a type
+type walkerFunc struct {
+	typ  types.Type
+	name string
+}
+
+// generator generates the walkers for the types of a package
+type generator struct {
+	pkg       *types.Package
+	imports   map[string]string
+	names     map[string]bool
+	used      map[string]bool
+	funcs     []walkerFunc
+	generated int
+	body      bytes.Buffer
+}
+
+// generate loads the package in the given directory, excluding the output file, and returns the formatted source of
+// walkers for the named types. The args are the command line arguments, for the header of the generated source.
+func generate(dir, output string, typeNames []string, args string) ([]byte, error) {
+	pkg, err := loadPackage(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		imports: map[string]string{"reflect": "reflect", "sort": "sort", goreflectPath: "goreflect"},
+		names:   map[string]bool{"reflect": true, "sort": true, "goreflect": true},
+		used:    map[string]bool{},
+	}
+
+	for _, typeName := range typeNames {
+		obj, isType := pkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !isType {
+			return nil, fmt.Errorf("type %s is not declared in package %s", typeName, pkg.Name())
+		}
+
+		g.entry(typeName, obj.Type())
+	}
+
+	for g.generated < len(g.funcs) {
+		g.walker(g.funcs[g.generated])
+		g.generated++
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by goreflect-gen %s; DO NOT EDIT.\n\npackage %s\n\nimport (\n", args, pkg.Name())
+
+	importPaths := make([]string, 0, len(g.used))
+	for importPath := range g.used {
+		if importPath != goreflectPath {
+			importPaths = append(importPaths, importPath)
+		}
+	}
+
+	sort.Strings(importPaths)
+	for _, importPath := range importPaths {
+		if name := g.imports[importPath]; name != path.Base(importPath) {
+			fmt.Fprintf(&src, "\t%s %s\n", name, strconv.Quote(importPath))
+		} else {
+			fmt.Fprintf(&src, "\t%s\n", strconv.Quote(importPath))
+		}
+	}
+
+	fmt.Fprintf(&src, "\n\t%s\n)\n", strconv.Quote(goreflectPath))
+	src.Write(g.body.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+// loadPackage parses and type checks the package in the given directory, excluding the given file
+func loadPackage(dir, exclude string) (*types.Package, error) {
+	bpkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fset  = token.NewFileSet()
+		files []*ast.File
+	)
+
+	for _, name := range bpkg.GoFiles {
+		if name == exclude {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(bpkg.Name, fset, files, nil)
+}
+
+// qualifier returns the name of the import of a package, adding the import if it has not been added yet.
+// Types of the generated package are not qualified.
+// Only the imports that are returned by qualifier or passed to use are imported by the generated source.
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+
+	g.used[pkg.Path()] = true
+	if name, imported := g.imports[pkg.Path()]; imported {
+		return name
+	}
+
+	name := pkg.Name()
+	for i := 2; g.names[name]; i++ {
+		name = pkg.Name() + strconv.Itoa(i)
+	}
+
+	g.imports[pkg.Path()], g.names[name] = name, true
+	return name
+}
+
+// use records that the generated source uses the given import paths
+func (g *generator) use(paths ...string) {
+	for _, path := range paths {
+		g.used[path] = true
+	}
+}
+
+// typeString returns a type as it is written in the generated package
+func (g *generator) typeString(typ types.Type) string {
+	return types.TypeString(typ, g.qualifier)
+}
+
+// nameable returns true if a type can be written in the generated package
+func (g *generator) nameable(typ types.Type) bool {
+	switch t := typ.(type) {
+	case *types.Basic:
+		return t.Kind() != types.UnsafePointer
+
+	case *types.Named:
+		obj := t.Obj()
+		return (obj.Pkg() == nil) || ((obj.Parent() == obj.Pkg().Scope()) && ((obj.Pkg() == g.pkg) || obj.Exported()))
+
+	case *types.Pointer:
+		return g.nameable(t.Elem())
+
+	case *types.Array:
+		return g.nameable(t.Elem())
+
+	case *types.Slice:
+		return g.nameable(t.Elem())
+
+	case *types.Chan:
+		return g.nameable(t.Elem())
+
+	case *types.Map:
+		return g.nameable(t.Key()) && g.nameable(t.Elem())
+
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if f := t.Field(i); !g.accessible(f) || !g.nameable(f.Type()) {
+				return false
+			}
+		}
+
+		return true
+
+	case *types.Signature:
+		return g.nameable(t.Params()) && g.nameable(t.Results())
+
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if !g.nameable(t.At(i).Type()) {
+				return false
+			}
+		}
+
+		return true
+
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if m := t.Method(i); !g.accessible(m) || !g.nameable(m.Type()) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// accessible returns true if a field or method can be accessed by name in the generated package
+func (g *generator) accessible(obj types.Object) bool {
+	return (obj.Name() != "_") && (obj.Exported() || (obj.Pkg() == g.pkg))
+}
+
+// isGenerated returns true if the components of values of a type are walked by generated code.
+// Interfaces, and structs with fields that cannot be accessed, are walked with reflection.
+func (g *generator) isGenerated(typ types.Type) bool {
+	if !g.nameable(typ) {
+		return false
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer, *types.Array, *types.Slice, *types.Map:
+		return true
+
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !g.accessible(t.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// funcFor returns the name of the generated func that walks a type, adding it if it has not been added yet
+func (g *generator) funcFor(typ types.Type) string {
+	for _, f := range g.funcs {
+		if types.Identical(f.typ, typ) {
+			return f.name
+		}
+	}
+
+	name := "goreflectWalk" + strconv.Itoa(len(g.funcs)+1)
+	g.funcs = append(g.funcs, walkerFunc{typ: typ, name: name})
+
+	return name
+}
+
+// valueOf returns an expression for the reflect.Value of an addressable expression of a type, which has the same type
+// as the expression, even if it is an interface
+func (g *generator) valueOf(expr string, typ types.Type) string {
+	g.use("reflect")
+	if types.IsInterface(typ) {
+		return "reflect.ValueOf(&" + expr + ").Elem()"
+	}
+
+	return "reflect.ValueOf(" + expr + ")"
+}
+
+// walk returns the statement that walks an addressable expression of a type
+func (g *generator) walk(expr string, typ types.Type) string {
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		if method, visited := visitMethods[t.Kind()]; visited {
+			return fmt.Sprintf("g.Visitor().%s(%s(%s))", method, types.Typ[t.Kind()].Name(), expr)
+		}
+
+	case *types.Chan:
+		g.use("reflect")
+		return "g.Visitor().VisitChan(reflect.ValueOf(" + expr + "))"
+
+	case *types.Signature:
+		g.use("reflect")
+		return "g.Visitor().VisitFunc(reflect.ValueOf(" + expr + "))"
+	}
+
+	if g.isGenerated(typ) {
+		return g.funcFor(typ) + "(g, " + expr + ")"
+	}
+
+	return "g.Dispatch(" + g.valueOf(expr, typ) + ")"
+}
+
+// entry writes the exported func that walks a named type
+func (g *generator) entry(typeName string, typ types.Type) {
+	walk := g.walk("x", typ)
+	if types.IsInterface(typ) {
+		// As for ValueDepthFirstWalker.Walk, the value is the value contained in the interface
+		g.use("reflect")
+		walk = "g.Dispatch(reflect.ValueOf(x))"
+	}
+
+	fmt.Fprintf(
+		&g.body,
+		`
+// Walk%[1]s walks x in the same way as w.Walk(x), calling the same visitor methods in the same order
+func Walk%[1]s(w goreflect.ValueDepthFirstWalker, x %[1]s) {
+	g := goreflect.NewGeneratedWalker(w)
+	%[2]s
+}
+`,
+		typeName,
+		walk,
+	)
+}
+
+// walker writes a generated func that walks a pointer, array, slice, map, or struct type
+func (g *generator) walker(f walkerFunc) {
+	typeString := g.typeString(f.typ)
+
+	// Every generated func uses reflect.ValueOf
+	g.use("reflect")
+	fmt.Fprintf(&g.body, "\n// %s walks values of type %s\nfunc %s(g *goreflect.GeneratedWalker, x %s) {\n", f.name, typeString, f.name, typeString)
+
+	switch t := f.typ.Underlying().(type) {
+	case *types.Pointer:
+		fmt.Fprintf(
+			&g.body,
+			`if x == nil {
+	g.Visitor().VisitNil(reflect.ValueOf(x))
+	return
+}
+
+v := reflect.ValueOf(x)
+if !g.Enter(v) {
+	return
+}
+
+if g.PrePtr(v) {
+	g.Push(goreflect.ValuePathElement{Kind: goreflect.PathDeref})
+	%s
+	g.Pop()
+}
+
+if g.Stopped() {
+	return
+}
+
+g.Visitor().VisitPostPtr(v)
+g.Leave(v)
+`,
+			g.walk("*x", t.Elem()),
+		)
+
+	case *types.Array:
+		fmt.Fprintf(
+			&g.body,
+			`v, n := reflect.ValueOf(x), len(x)
+if g.PreArray(n, v) {
+	for i := 0; i < n; i++ {
+		e := %s
+
+		g.Visitor().VisitPreArrayIndex(n, i, e)
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+		%s
+		g.Pop()
+
+		if g.Stopped() {
+			return
+		}
+
+		g.Visitor().VisitPostArrayIndex(n, i, e)
+	}
+}
+
+if g.Stopped() {
+	return
+}
+
+g.Visitor().VisitPostArray(n, v)
+`,
+			g.valueOf("x[i]", t.Elem()),
+			g.walk("x[i]", t.Elem()),
+		)
+
+	case *types.Slice:
+		fmt.Fprintf(
+			&g.body,
+			`v := reflect.ValueOf(x)
+if !g.Enter(v) {
+	return
+}
+
+n := len(x)
+if g.PreSlice(n, v) {
+	for i := 0; i < n; i++ {
+		e := %s
+
+		g.Visitor().VisitPreSliceIndex(n, i, e)
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathIndex, Index: i})
+		%s
+		g.Pop()
+
+		if g.Stopped() {
+			return
+		}
+
+		g.Visitor().VisitPostSliceIndex(n, i, e)
+	}
+}
+
+if g.Stopped() {
+	return
+}
+
+g.Visitor().VisitPostSlice(n, v)
+g.Leave(v)
+`,
+			g.valueOf("x[i]", t.Elem()),
+			g.walk("x[i]", t.Elem()),
+		)
+
+	case *types.Map:
+		// As for ValueDepthFirstWalker, the values are collected with the keys, as NaN cannot index the map,
+		// and a stable sort keeps keys that compare equal in the same order
+		g.use("sort")
+		entryType := "struct{ k " + g.typeString(t.Key()) + "; v " + g.typeString(t.Elem()) + " }"
+		fmt.Fprintf(
+			&g.body,
+			`v := reflect.ValueOf(x)
+if !g.Enter(v) {
+	return
+}
+
+n := len(x)
+if g.PreMap(n, v) {
+	entries := make([]%s, 0, n)
+	for k, mv := range x {
+		entries = append(entries, %s{k, mv})
+	}
+
+	if less := g.MapKeyLess(); less != nil {
+		sort.SliceStable(entries, func(a, b int) bool {
+			return less(%s, %s)
+		})
+	}
+
+	for i, e := range entries {
+		k, mv := e.k, e.v
+		mk, me := %s, %s
+
+		g.Visitor().VisitPreMapKeyValue(n, i, mk, me)
+
+		g.Visitor().VisitPreMapKey(n, i, mk)
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapKey, Index: i, Key: mk})
+		%s
+		g.Pop()
+
+		if g.Stopped() {
+			return
+		}
+
+		g.Visitor().VisitPostMapKey(n, i, mk)
+
+		g.Visitor().VisitPreMapValue(n, i, me)
+		g.Push(goreflect.ValuePathElement{Kind: goreflect.PathMapValue, Index: i, Key: mk})
+		%s
+		g.Pop()
+
+		if g.Stopped() {
+			return
+		}
+
+		g.Visitor().VisitPostMapValue(n, i, me)
+
+		g.Visitor().VisitPostMapKeyValue(n, i, mk, me)
+	}
+}
+
+if g.Stopped() {
+	return
+}
+
+g.Visitor().VisitPostMap(n, v)
+g.Leave(v)
+`,
+			entryType,
+			entryType,
+			g.valueOf("entries[a].k", t.Key()),
+			g.valueOf("entries[b].k", t.Key()),
+			g.valueOf("k", t.Key()),
+			g.valueOf("mv", t.Elem()),
+			g.walk("k", t.Key()),
+			g.walk("mv", t.Elem()),
+		)
+
+	case *types.Struct:
+		fieldsName := strings.Replace(f.name, "Walk", "Fields", 1)
+		fmt.Fprintf(&g.body, "v, n := reflect.ValueOf(x), %d\n", t.NumFields())
+
+		if t.NumFields() == 0 {
+			g.body.WriteString("g.PreStruct(n, v)\n")
+		} else {
+			g.body.WriteString("if g.PreStruct(n, v) {\n")
+			for i := 0; i < t.NumFields(); i++ {
+				field := t.Field(i)
+				fmt.Fprintf(
+					&g.body,
+					`{
+	sf, sv := %[1]s[%[2]d], %[3]s
+
+	g.Visitor().VisitPreStructFieldValue(n, %[2]d, sf, sv)
+	g.Push(goreflect.ValuePathElement{Kind: goreflect.PathField, Index: %[2]d, Name: sf.Name})
+	%[4]s
+	g.Pop()
+
+	if g.Stopped() {
+		return
+	}
+
+	g.Visitor().VisitPostStructFieldValue(n, %[2]d, sf, sv)
+}
+`,
+					fieldsName,
+					i,
+					g.valueOf("x."+field.Name(), field.Type()),
+					g.walk("x."+field.Name(), field.Type()),
+				)
+			}
+			g.body.WriteString("}\n")
+		}
+
+		g.body.WriteString(`
+if g.Stopped() {
+	return
+}
+
+g.Visitor().VisitPostStruct(n, v)
+}
+`)
+
+		if t.NumFields() == 0 {
+			return
+		}
+
+		fmt.Fprintf(
+			&g.body,
+			"\n// %s are the fields of type %s\nvar %s = goreflect.GeneratedStructFields(reflect.TypeOf((*%s)(nil)).Elem())\n",
+			fieldsName,
+			typeString,
+			fieldsName,
+			typeString,
+		)
+
+		return
+	}
+
+	g.body.WriteString("}\n")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	// The generated walkers of the example package are up to date
+	expected, err := ioutil.ReadFile(filepath.Join("example", "goreflect_walkers.go"))
+	assert.Nil(t, err)
+
+	src, err := generate("example", "goreflect_walkers.go", []string{"Order", "Celsius", "Shape"}, "-types Order,Celsius,Shape")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(src))
+
+	// Only the imports that are used are imported, even if the name of an import appears in a name that is used
+	src, err = generate("example", "goreflect_walkers.go", []string{"Celsius"}, "-types Celsius")
+	assert.Nil(t, err)
+	assert.NotContains(t, string(src), `"reflect"`)
+	assert.NotContains(t, string(src), `"sort"`)
+	assert.Contains(t, string(src), "goreflect.NewGeneratedWalker(w)")
+
+	_, err = generate("example", "goreflect_walkers.go", []string{"Missing"}, "-types Missing")
+	assert.Equal(t, errors.New("type Missing is not declared in package example"), err)
+}
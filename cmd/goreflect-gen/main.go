@@ -0,0 +1,52 @@
+// Command goreflect-gen generates walkers for concrete types, that call the same ValueVisitor methods in the same order
+// as a goreflect.ValueDepthFirstWalker, without using reflection to walk the components of values.
+//
+// Usage:
+//
+//	goreflect-gen [-dir directory] [-output file] -types Type1,Type2,...
+//
+// The types are named types of the package in the directory (the current directory by default), and the generated
+// code is written to the output file in the same directory (goreflect_walkers.go by default). For each type T,
+// a func WalkT(w goreflect.ValueDepthFirstWalker, x T) is generated, that walks x with the visitor and options of w.
+// It can be used with go generate, eg:
+//
+//	//go:generate goreflect-gen -types Order,Customer
+//
+// See goreflect.GeneratedWalker for the options supported, and goreflect.CompareWalks to test that a generated walker
+// is equivalent to a ValueDepthFirstWalker.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		dir      = flag.String("dir", ".", "directory of the package that declares the types")
+		output   = flag.String("output", "goreflect_walkers.go", "name of the file to generate in the directory")
+		typeList = flag.String("types", "", "comma separated names of the types to generate walkers for")
+	)
+
+	flag.Parse()
+
+	if (*typeList == "") || (flag.NArg() > 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	typeNames := strings.Split(*typeList, ",")
+	src, err := generate(*dir, *output, typeNames, strings.Join(os.Args[1:], " "))
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(*dir, *output), src, 0644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goreflect-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,186 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeDepthFirstWalker visits a type in a depth first traversal, without requiring a value of the type.
+// A walk begins with the Walk method, which accepts a type to walk.
+// A walker instance is reuseable, it can be called many times wth different types and/or different TypeVisitors.
+//
+// Example call sequence to visit a map[string][]*int:
+// Walk(map[string][]*int)
+// VisitPreMapType(map[string][]*int)
+// VisitPreMapKeyType(string)
+// VisitScalarType(string)
+// VisitPostMapKeyType(string)
+// VisitPreMapElemType([]*int)
+// VisitPreSliceType([]*int)
+// VisitPreSliceElemType(*int)
+// VisitPrePtrType(*int)
+// VisitScalarType(int)
+// VisitPostPtrType(*int)
+// VisitPostSliceElemType(*int)
+// VisitPostSliceType([]*int)
+// VisitPostMapElemType([]*int)
+// VisitPostMapType(map[string][]*int)
+//
+// Interface types are visited without walking their methods.
+//
+// A type that refers to itself, such as type Node struct{ Next *Node }, would never terminate if it were walked again.
+// When a ptr, array, slice, map, chan, struct, or func type is encountered that is still being walked,
+// VisitRecursiveType is called instead of walking it again.
+type TypeDepthFirstWalker struct {
+	visitor TypeVisitor
+}
+
+// NewTypeDepthFirstWalker constructs a TypeDepthFirstWalker with an optional TypeVisitor
+func NewTypeDepthFirstWalker(visitor ...TypeVisitor) TypeDepthFirstWalker {
+	var vis TypeVisitor
+	if len(visitor) > 0 {
+		vis = visitor[0]
+	}
+
+	return TypeDepthFirstWalker{visitor: vis}
+}
+
+// WithVisitor sets the visitor to walk
+func (w *TypeDepthFirstWalker) WithVisitor(visitor TypeVisitor) {
+	w.visitor = visitor
+}
+
+// Walk walks the given type in a depth-first traversal.
+// The type passed can be a reflect.Type, or a value or reflect.Value wrapper of the type.
+// The Walk can be invoked multiple times with different types, as each walk begins by calling the Init() method the visitor given in the costructor.
+// There is no return result from the walk. Instead, the visitor is expected to have a Result() method that returns the appropriate type.
+func (w TypeDepthFirstWalker) Walk(typ interface{}) {
+	t := GetReflectTypeOf(typ)
+	if t == nil {
+		panic(fmt.Errorf("goreflect.TypeDepthFirstWalker.Walk: the type of nil cannot be walked"))
+	}
+
+	w.visitor.Init()
+	w.dispatch(t, map[reflect.Type]bool{})
+}
+
+// Dispatch executes the appropriate visitor methods for a type based on the kind.
+// The walking map contains the types that are being walked.
+func (w TypeDepthFirstWalker) dispatch(t reflect.Type, walking map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String, reflect.UnsafePointer:
+		w.visitor.VisitScalarType(t)
+		return
+
+	case reflect.Interface:
+		w.visitor.VisitInterfaceType(t)
+		return
+	}
+
+	if walking[t] {
+		w.visitor.VisitRecursiveType(t)
+		return
+	}
+
+	walking[t] = true
+	defer delete(walking, t)
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		w.visitor.VisitPrePtrType(t)
+		w.dispatch(t.Elem(), walking)
+		w.visitor.VisitPostPtrType(t)
+
+	case reflect.Array:
+		{
+			n, e := t.Len(), t.Elem()
+
+			w.visitor.VisitPreArrayType(n, t)
+			w.visitor.VisitPreArrayElemType(n, e)
+			w.dispatch(e, walking)
+			w.visitor.VisitPostArrayElemType(n, e)
+			w.visitor.VisitPostArrayType(n, t)
+		}
+
+	case reflect.Slice:
+		{
+			e := t.Elem()
+
+			w.visitor.VisitPreSliceType(t)
+			w.visitor.VisitPreSliceElemType(e)
+			w.dispatch(e, walking)
+			w.visitor.VisitPostSliceElemType(e)
+			w.visitor.VisitPostSliceType(t)
+		}
+
+	case reflect.Map:
+		{
+			k, e := t.Key(), t.Elem()
+
+			w.visitor.VisitPreMapType(t)
+
+			w.visitor.VisitPreMapKeyType(k)
+			w.dispatch(k, walking)
+			w.visitor.VisitPostMapKeyType(k)
+
+			w.visitor.VisitPreMapElemType(e)
+			w.dispatch(e, walking)
+			w.visitor.VisitPostMapElemType(e)
+
+			w.visitor.VisitPostMapType(t)
+		}
+
+	case reflect.Chan:
+		{
+			e := t.Elem()
+
+			w.visitor.VisitPreChanType(t)
+			w.visitor.VisitPreChanElemType(e)
+			w.dispatch(e, walking)
+			w.visitor.VisitPostChanElemType(e)
+			w.visitor.VisitPostChanType(t)
+		}
+
+	case reflect.Struct:
+		{
+			n := t.NumField()
+
+			w.visitor.VisitPreStructType(n, t)
+			for i := 0; i < n; i++ {
+				f := t.Field(i)
+
+				w.visitor.VisitPreStructFieldType(n, i, f)
+				w.dispatch(f.Type, walking)
+				w.visitor.VisitPostStructFieldType(n, i, f)
+			}
+			w.visitor.VisitPostStructType(n, t)
+		}
+
+	case reflect.Func:
+		{
+			numIn, numOut := t.NumIn(), t.NumOut()
+
+			w.visitor.VisitPreFuncType(t)
+			for i := 0; i < numIn; i++ {
+				in := t.In(i)
+
+				w.visitor.VisitPreFuncInType(numIn, i, in)
+				w.dispatch(in, walking)
+				w.visitor.VisitPostFuncInType(numIn, i, in)
+			}
+
+			for i := 0; i < numOut; i++ {
+				out := t.Out(i)
+
+				w.visitor.VisitPreFuncOutType(numOut, i, out)
+				w.dispatch(out, walking)
+				w.visitor.VisitPostFuncOutType(numOut, i, out)
+			}
+			w.visitor.VisitPostFuncType(t)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeWalkerTestNode struct {
+	Name     string
+	Children []*typeWalkerTestNode
+	Handler  func(*typeWalkerTestNode) error
+}
+
+// typeWalkerTestRecorder records the type of every visit
+type typeWalkerTestRecorder struct {
+	visits []string
+}
+
+func (r *typeWalkerTestRecorder) Init() {
+	r.visits = nil
+}
+
+func (r *typeWalkerTestRecorder) record(method string, t interface{}) {
+	r.visits = append(r.visits, fmt.Sprintf("%s(%v)", method, t))
+}
+
+func (r *typeWalkerTestRecorder) VisitScalarType(t reflect.Type) {
+	r.record("Scalar", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitInterfaceType(t reflect.Type) {
+	r.record("Interface", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPrePtrType(t reflect.Type) {
+	r.record("PrePtr", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreArrayElemType(length int, t reflect.Type) {
+	r.record(fmt.Sprintf("PreArrayElem[%d]", length), t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreSliceElemType(t reflect.Type) {
+	r.record("PreSliceElem", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreMapKeyType(t reflect.Type) {
+	r.record("PreMapKey", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreMapElemType(t reflect.Type) {
+	r.record("PreMapElem", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreChanType(t reflect.Type) {
+	r.record("PreChan", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreChanElemType(t reflect.Type) {
+	r.record("PreChanElem", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPostChanElemType(t reflect.Type) {
+	r.record("PostChanElem", t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreStructFieldType(_ int, _ int, f reflect.StructField) {
+	r.record("PreStructField", f.Name)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreFuncInType(_ int, index int, t reflect.Type) {
+	r.record(fmt.Sprintf("PreFuncIn[%d]", index), t)
+}
+
+func (r *typeWalkerTestRecorder) VisitPreFuncOutType(_ int, index int, t reflect.Type) {
+	r.record(fmt.Sprintf("PreFuncOut[%d]", index), t)
+}
+
+func (r *typeWalkerTestRecorder) VisitRecursiveType(t reflect.Type) {
+	r.record("Recursive", t)
+}
+
+func TestTypeDepthFirstWalker(t *testing.T) {
+	var (
+		r = &typeWalkerTestRecorder{}
+		w = NewTypeDepthFirstWalker(NewTypeVisitorAdapter(r))
+	)
+
+	// Types can be given as a reflect.Type, a value, or a reflect.Value
+	w.Walk(reflect.TypeOf(map[string][]*int{}))
+	assert.Equal(
+		t,
+		[]string{
+			"PreMapKey(string)",
+			"Scalar(string)",
+			"PreMapElem([]*int)",
+			"PreSliceElem(*int)",
+			"PrePtr(*int)",
+			"Scalar(int)",
+		},
+		r.visits,
+	)
+
+	w.Walk(reflect.ValueOf([2]chan error{}))
+	assert.Equal(
+		t,
+		[]string{
+			"PreArrayElem[2](chan error)",
+			"PreChan(chan error)",
+			"PreChanElem(error)",
+			"Interface(error)",
+			"PostChanElem(error)",
+		},
+		r.visits,
+	)
+
+	// Recursive types
+	w.Walk(typeWalkerTestNode{})
+	assert.Equal(
+		t,
+		[]string{
+			"PreStructField(Name)",
+			"Scalar(string)",
+			"PreStructField(Children)",
+			"PreSliceElem(*goreflect.typeWalkerTestNode)",
+			"PrePtr(*goreflect.typeWalkerTestNode)",
+			"Recursive(goreflect.typeWalkerTestNode)",
+			"PreStructField(Handler)",
+			"PreFuncIn[0](*goreflect.typeWalkerTestNode)",
+			"PrePtr(*goreflect.typeWalkerTestNode)",
+			"Recursive(goreflect.typeWalkerTestNode)",
+			"PreFuncOut[0](error)",
+			"Interface(error)",
+		},
+		r.visits,
+	)
+
+	// A type that is used more than once, but not recursively, is walked each time
+	w.Walk(struct{ A, B *int }{})
+	assert.Equal(
+		t,
+		[]string{
+			"PreStructField(A)",
+			"PrePtr(*int)",
+			"Scalar(int)",
+			"PreStructField(B)",
+			"PrePtr(*int)",
+			"Scalar(int)",
+		},
+		r.visits,
+	)
+
+	// Nil has no type
+	func() {
+		defer func() {
+			assert.Equal(t, fmt.Errorf("goreflect.TypeDepthFirstWalker.Walk: the type of nil cannot be walked"), recover())
+		}()
+
+		w.Walk(nil)
+		assert.Fail(t, "Must panic")
+	}()
+}
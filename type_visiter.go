@@ -0,0 +1,208 @@
+package goreflect
+
+import (
+	"reflect"
+)
+
+// ScalarTypeVisitor visits bool, int, uint, float, complex, string, uintptr, and unsafe pointer types
+type ScalarTypeVisitor interface {
+	VisitScalarType(t reflect.Type)
+}
+
+// InterfaceTypeVisitor visits interface types
+type InterfaceTypeVisitor interface {
+	VisitInterfaceType(t reflect.Type)
+}
+
+// PrePtrTypeVisitor previsits ptr types
+type PrePtrTypeVisitor interface {
+	VisitPrePtrType(t reflect.Type)
+}
+
+// PostPtrTypeVisitor postvisits ptr types
+type PostPtrTypeVisitor interface {
+	VisitPostPtrType(t reflect.Type)
+}
+
+// PreArrayTypeVisitor previsits array types
+type PreArrayTypeVisitor interface {
+	VisitPreArrayType(length int, t reflect.Type)
+}
+
+// PreArrayElemTypeVisitor previsits array element types
+type PreArrayElemTypeVisitor interface {
+	VisitPreArrayElemType(length int, t reflect.Type)
+}
+
+// PostArrayElemTypeVisitor postvisits array element types
+type PostArrayElemTypeVisitor interface {
+	VisitPostArrayElemType(length int, t reflect.Type)
+}
+
+// PostArrayTypeVisitor postvisits array types
+type PostArrayTypeVisitor interface {
+	VisitPostArrayType(length int, t reflect.Type)
+}
+
+// PreSliceTypeVisitor previsits slice types
+type PreSliceTypeVisitor interface {
+	VisitPreSliceType(t reflect.Type)
+}
+
+// PreSliceElemTypeVisitor previsits slice element types
+type PreSliceElemTypeVisitor interface {
+	VisitPreSliceElemType(t reflect.Type)
+}
+
+// PostSliceElemTypeVisitor postvisits slice element types
+type PostSliceElemTypeVisitor interface {
+	VisitPostSliceElemType(t reflect.Type)
+}
+
+// PostSliceTypeVisitor postvisits slice types
+type PostSliceTypeVisitor interface {
+	VisitPostSliceType(t reflect.Type)
+}
+
+// PreMapTypeVisitor previsits map types
+type PreMapTypeVisitor interface {
+	VisitPreMapType(t reflect.Type)
+}
+
+// PreMapKeyTypeVisitor previsits map key types
+type PreMapKeyTypeVisitor interface {
+	VisitPreMapKeyType(t reflect.Type)
+}
+
+// PostMapKeyTypeVisitor postvisits map key types
+type PostMapKeyTypeVisitor interface {
+	VisitPostMapKeyType(t reflect.Type)
+}
+
+// PreMapElemTypeVisitor previsits map element types
+type PreMapElemTypeVisitor interface {
+	VisitPreMapElemType(t reflect.Type)
+}
+
+// PostMapElemTypeVisitor postvisits map element types
+type PostMapElemTypeVisitor interface {
+	VisitPostMapElemType(t reflect.Type)
+}
+
+// PostMapTypeVisitor postvisits map types
+type PostMapTypeVisitor interface {
+	VisitPostMapType(t reflect.Type)
+}
+
+// PreChanTypeVisitor previsits chan types
+type PreChanTypeVisitor interface {
+	VisitPreChanType(t reflect.Type)
+}
+
+// PreChanElemTypeVisitor previsits chan element types
+type PreChanElemTypeVisitor interface {
+	VisitPreChanElemType(t reflect.Type)
+}
+
+// PostChanElemTypeVisitor postvisits chan element types
+type PostChanElemTypeVisitor interface {
+	VisitPostChanElemType(t reflect.Type)
+}
+
+// PostChanTypeVisitor postvisits chan types
+type PostChanTypeVisitor interface {
+	VisitPostChanType(t reflect.Type)
+}
+
+// PreStructTypeVisitor previsits struct types
+type PreStructTypeVisitor interface {
+	VisitPreStructType(numFields int, t reflect.Type)
+}
+
+// PreStructFieldTypeVisitor previsits struct fields
+type PreStructFieldTypeVisitor interface {
+	VisitPreStructFieldType(numFields int, index int, f reflect.StructField)
+}
+
+// PostStructFieldTypeVisitor postvisits struct fields
+type PostStructFieldTypeVisitor interface {
+	VisitPostStructFieldType(numFields int, index int, f reflect.StructField)
+}
+
+// PostStructTypeVisitor postvisits struct types
+type PostStructTypeVisitor interface {
+	VisitPostStructType(numFields int, t reflect.Type)
+}
+
+// PreFuncTypeVisitor previsits func types
+type PreFuncTypeVisitor interface {
+	VisitPreFuncType(t reflect.Type)
+}
+
+// PreFuncInTypeVisitor previsits func parameter types
+type PreFuncInTypeVisitor interface {
+	VisitPreFuncInType(numIn int, index int, t reflect.Type)
+}
+
+// PostFuncInTypeVisitor postvisits func parameter types
+type PostFuncInTypeVisitor interface {
+	VisitPostFuncInType(numIn int, index int, t reflect.Type)
+}
+
+// PreFuncOutTypeVisitor previsits func result types
+type PreFuncOutTypeVisitor interface {
+	VisitPreFuncOutType(numOut int, index int, t reflect.Type)
+}
+
+// PostFuncOutTypeVisitor postvisits func result types
+type PostFuncOutTypeVisitor interface {
+	VisitPostFuncOutType(numOut int, index int, t reflect.Type)
+}
+
+// PostFuncTypeVisitor postvisits func types
+type PostFuncTypeVisitor interface {
+	VisitPostFuncType(t reflect.Type)
+}
+
+// RecursiveTypeVisitor visits types that refer back to a type that is still being walked, instead of walking them again
+type RecursiveTypeVisitor interface {
+	VisitRecursiveType(t reflect.Type)
+}
+
+// TypeVisitor combines all above interfaces into one, with InitVisitor
+type TypeVisitor interface {
+	InitVisitor
+	ScalarTypeVisitor
+	InterfaceTypeVisitor
+	PrePtrTypeVisitor
+	PostPtrTypeVisitor
+	PreArrayTypeVisitor
+	PreArrayElemTypeVisitor
+	PostArrayElemTypeVisitor
+	PostArrayTypeVisitor
+	PreSliceTypeVisitor
+	PreSliceElemTypeVisitor
+	PostSliceElemTypeVisitor
+	PostSliceTypeVisitor
+	PreMapTypeVisitor
+	PreMapKeyTypeVisitor
+	PostMapKeyTypeVisitor
+	PreMapElemTypeVisitor
+	PostMapElemTypeVisitor
+	PostMapTypeVisitor
+	PreChanTypeVisitor
+	PreChanElemTypeVisitor
+	PostChanElemTypeVisitor
+	PostChanTypeVisitor
+	PreStructTypeVisitor
+	PreStructFieldTypeVisitor
+	PostStructFieldTypeVisitor
+	PostStructTypeVisitor
+	PreFuncTypeVisitor
+	PreFuncInTypeVisitor
+	PostFuncInTypeVisitor
+	PreFuncOutTypeVisitor
+	PostFuncOutTypeVisitor
+	PostFuncTypeVisitor
+	RecursiveTypeVisitor
+}
@@ -0,0 +1,408 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeVisitorAdapter composes any subset of interfaces defined in TypeVisitor into a full TypeVisitor implementation.
+// Unimplemented interfaces are filled in with empty implementations.
+type TypeVisitorAdapter struct {
+	initVisitor                func()
+	scalarTypeVisitor          func(reflect.Type)
+	interfaceTypeVisitor       func(reflect.Type)
+	prePtrTypeVisitor          func(reflect.Type)
+	postPtrTypeVisitor         func(reflect.Type)
+	preArrayTypeVisitor        func(int, reflect.Type)
+	preArrayElemTypeVisitor    func(int, reflect.Type)
+	postArrayElemTypeVisitor   func(int, reflect.Type)
+	postArrayTypeVisitor       func(int, reflect.Type)
+	preSliceTypeVisitor        func(reflect.Type)
+	preSliceElemTypeVisitor    func(reflect.Type)
+	postSliceElemTypeVisitor   func(reflect.Type)
+	postSliceTypeVisitor       func(reflect.Type)
+	preMapTypeVisitor          func(reflect.Type)
+	preMapKeyTypeVisitor       func(reflect.Type)
+	postMapKeyTypeVisitor      func(reflect.Type)
+	preMapElemTypeVisitor      func(reflect.Type)
+	postMapElemTypeVisitor     func(reflect.Type)
+	postMapTypeVisitor         func(reflect.Type)
+	preChanTypeVisitor         func(reflect.Type)
+	preChanElemTypeVisitor     func(reflect.Type)
+	postChanElemTypeVisitor    func(reflect.Type)
+	postChanTypeVisitor        func(reflect.Type)
+	preStructTypeVisitor       func(int, reflect.Type)
+	preStructFieldTypeVisitor  func(int, int, reflect.StructField)
+	postStructFieldTypeVisitor func(int, int, reflect.StructField)
+	postStructTypeVisitor      func(int, reflect.Type)
+	preFuncTypeVisitor         func(reflect.Type)
+	preFuncInTypeVisitor       func(int, int, reflect.Type)
+	postFuncInTypeVisitor      func(int, int, reflect.Type)
+	preFuncOutTypeVisitor      func(int, int, reflect.Type)
+	postFuncOutTypeVisitor     func(int, int, reflect.Type)
+	postFuncTypeVisitor        func(reflect.Type)
+	recursiveTypeVisitor       func(reflect.Type)
+}
+
+// NewTypeVisitorAdapter constructs a TypeVisitorAdapter
+func NewTypeVisitorAdapter(visitor ...interface{}) *TypeVisitorAdapter {
+	ta := &TypeVisitorAdapter{}
+
+	if len(visitor) > 0 {
+		ta.WithVisitor(visitor[0])
+	}
+
+	return ta
+}
+
+// WithVisitor sets the visitor to use for future calls to Walk
+func (ta *TypeVisitorAdapter) WithVisitor(visitor interface{}) *TypeVisitorAdapter {
+	if visitor == nil {
+		panic(fmt.Errorf("goreflect.TypeVisitorAdapter.WithVisitor: visitor cannot be nil"))
+	}
+
+	// Compose TypeVisitor with a combination of interfaces implemented by the given visitor,
+	// and empty functions for unimplemented interfaces.
+
+	ta.initVisitor = func() {}
+	if initv, ok := visitor.(InitVisitor); ok {
+		ta.initVisitor = initv.Init
+	}
+
+	ta.scalarTypeVisitor = func(reflect.Type) {}
+	if scalarTypev, ok := visitor.(ScalarTypeVisitor); ok {
+		ta.scalarTypeVisitor = scalarTypev.VisitScalarType
+	}
+
+	ta.interfaceTypeVisitor = func(reflect.Type) {}
+	if interfaceTypev, ok := visitor.(InterfaceTypeVisitor); ok {
+		ta.interfaceTypeVisitor = interfaceTypev.VisitInterfaceType
+	}
+
+	ta.prePtrTypeVisitor = func(reflect.Type) {}
+	if prePtrTypev, ok := visitor.(PrePtrTypeVisitor); ok {
+		ta.prePtrTypeVisitor = prePtrTypev.VisitPrePtrType
+	}
+
+	ta.postPtrTypeVisitor = func(reflect.Type) {}
+	if postPtrTypev, ok := visitor.(PostPtrTypeVisitor); ok {
+		ta.postPtrTypeVisitor = postPtrTypev.VisitPostPtrType
+	}
+
+	ta.preArrayTypeVisitor = func(int, reflect.Type) {}
+	if preArrayTypev, ok := visitor.(PreArrayTypeVisitor); ok {
+		ta.preArrayTypeVisitor = preArrayTypev.VisitPreArrayType
+	}
+
+	ta.preArrayElemTypeVisitor = func(int, reflect.Type) {}
+	if preArrayElemTypev, ok := visitor.(PreArrayElemTypeVisitor); ok {
+		ta.preArrayElemTypeVisitor = preArrayElemTypev.VisitPreArrayElemType
+	}
+
+	ta.postArrayElemTypeVisitor = func(int, reflect.Type) {}
+	if postArrayElemTypev, ok := visitor.(PostArrayElemTypeVisitor); ok {
+		ta.postArrayElemTypeVisitor = postArrayElemTypev.VisitPostArrayElemType
+	}
+
+	ta.postArrayTypeVisitor = func(int, reflect.Type) {}
+	if postArrayTypev, ok := visitor.(PostArrayTypeVisitor); ok {
+		ta.postArrayTypeVisitor = postArrayTypev.VisitPostArrayType
+	}
+
+	ta.preSliceTypeVisitor = func(reflect.Type) {}
+	if preSliceTypev, ok := visitor.(PreSliceTypeVisitor); ok {
+		ta.preSliceTypeVisitor = preSliceTypev.VisitPreSliceType
+	}
+
+	ta.preSliceElemTypeVisitor = func(reflect.Type) {}
+	if preSliceElemTypev, ok := visitor.(PreSliceElemTypeVisitor); ok {
+		ta.preSliceElemTypeVisitor = preSliceElemTypev.VisitPreSliceElemType
+	}
+
+	ta.postSliceElemTypeVisitor = func(reflect.Type) {}
+	if postSliceElemTypev, ok := visitor.(PostSliceElemTypeVisitor); ok {
+		ta.postSliceElemTypeVisitor = postSliceElemTypev.VisitPostSliceElemType
+	}
+
+	ta.postSliceTypeVisitor = func(reflect.Type) {}
+	if postSliceTypev, ok := visitor.(PostSliceTypeVisitor); ok {
+		ta.postSliceTypeVisitor = postSliceTypev.VisitPostSliceType
+	}
+
+	ta.preMapTypeVisitor = func(reflect.Type) {}
+	if preMapTypev, ok := visitor.(PreMapTypeVisitor); ok {
+		ta.preMapTypeVisitor = preMapTypev.VisitPreMapType
+	}
+
+	ta.preMapKeyTypeVisitor = func(reflect.Type) {}
+	if preMapKeyTypev, ok := visitor.(PreMapKeyTypeVisitor); ok {
+		ta.preMapKeyTypeVisitor = preMapKeyTypev.VisitPreMapKeyType
+	}
+
+	ta.postMapKeyTypeVisitor = func(reflect.Type) {}
+	if postMapKeyTypev, ok := visitor.(PostMapKeyTypeVisitor); ok {
+		ta.postMapKeyTypeVisitor = postMapKeyTypev.VisitPostMapKeyType
+	}
+
+	ta.preMapElemTypeVisitor = func(reflect.Type) {}
+	if preMapElemTypev, ok := visitor.(PreMapElemTypeVisitor); ok {
+		ta.preMapElemTypeVisitor = preMapElemTypev.VisitPreMapElemType
+	}
+
+	ta.postMapElemTypeVisitor = func(reflect.Type) {}
+	if postMapElemTypev, ok := visitor.(PostMapElemTypeVisitor); ok {
+		ta.postMapElemTypeVisitor = postMapElemTypev.VisitPostMapElemType
+	}
+
+	ta.postMapTypeVisitor = func(reflect.Type) {}
+	if postMapTypev, ok := visitor.(PostMapTypeVisitor); ok {
+		ta.postMapTypeVisitor = postMapTypev.VisitPostMapType
+	}
+
+	ta.preChanTypeVisitor = func(reflect.Type) {}
+	if preChanTypev, ok := visitor.(PreChanTypeVisitor); ok {
+		ta.preChanTypeVisitor = preChanTypev.VisitPreChanType
+	}
+
+	ta.preChanElemTypeVisitor = func(reflect.Type) {}
+	if preChanElemTypev, ok := visitor.(PreChanElemTypeVisitor); ok {
+		ta.preChanElemTypeVisitor = preChanElemTypev.VisitPreChanElemType
+	}
+
+	ta.postChanElemTypeVisitor = func(reflect.Type) {}
+	if postChanElemTypev, ok := visitor.(PostChanElemTypeVisitor); ok {
+		ta.postChanElemTypeVisitor = postChanElemTypev.VisitPostChanElemType
+	}
+
+	ta.postChanTypeVisitor = func(reflect.Type) {}
+	if postChanTypev, ok := visitor.(PostChanTypeVisitor); ok {
+		ta.postChanTypeVisitor = postChanTypev.VisitPostChanType
+	}
+
+	ta.preStructTypeVisitor = func(int, reflect.Type) {}
+	if preStructTypev, ok := visitor.(PreStructTypeVisitor); ok {
+		ta.preStructTypeVisitor = preStructTypev.VisitPreStructType
+	}
+
+	ta.preStructFieldTypeVisitor = func(int, int, reflect.StructField) {}
+	if preStructFieldTypev, ok := visitor.(PreStructFieldTypeVisitor); ok {
+		ta.preStructFieldTypeVisitor = preStructFieldTypev.VisitPreStructFieldType
+	}
+
+	ta.postStructFieldTypeVisitor = func(int, int, reflect.StructField) {}
+	if postStructFieldTypev, ok := visitor.(PostStructFieldTypeVisitor); ok {
+		ta.postStructFieldTypeVisitor = postStructFieldTypev.VisitPostStructFieldType
+	}
+
+	ta.postStructTypeVisitor = func(int, reflect.Type) {}
+	if postStructTypev, ok := visitor.(PostStructTypeVisitor); ok {
+		ta.postStructTypeVisitor = postStructTypev.VisitPostStructType
+	}
+
+	ta.preFuncTypeVisitor = func(reflect.Type) {}
+	if preFuncTypev, ok := visitor.(PreFuncTypeVisitor); ok {
+		ta.preFuncTypeVisitor = preFuncTypev.VisitPreFuncType
+	}
+
+	ta.preFuncInTypeVisitor = func(int, int, reflect.Type) {}
+	if preFuncInTypev, ok := visitor.(PreFuncInTypeVisitor); ok {
+		ta.preFuncInTypeVisitor = preFuncInTypev.VisitPreFuncInType
+	}
+
+	ta.postFuncInTypeVisitor = func(int, int, reflect.Type) {}
+	if postFuncInTypev, ok := visitor.(PostFuncInTypeVisitor); ok {
+		ta.postFuncInTypeVisitor = postFuncInTypev.VisitPostFuncInType
+	}
+
+	ta.preFuncOutTypeVisitor = func(int, int, reflect.Type) {}
+	if preFuncOutTypev, ok := visitor.(PreFuncOutTypeVisitor); ok {
+		ta.preFuncOutTypeVisitor = preFuncOutTypev.VisitPreFuncOutType
+	}
+
+	ta.postFuncOutTypeVisitor = func(int, int, reflect.Type) {}
+	if postFuncOutTypev, ok := visitor.(PostFuncOutTypeVisitor); ok {
+		ta.postFuncOutTypeVisitor = postFuncOutTypev.VisitPostFuncOutType
+	}
+
+	ta.postFuncTypeVisitor = func(reflect.Type) {}
+	if postFuncTypev, ok := visitor.(PostFuncTypeVisitor); ok {
+		ta.postFuncTypeVisitor = postFuncTypev.VisitPostFuncType
+	}
+
+	ta.recursiveTypeVisitor = func(reflect.Type) {}
+	if recursiveTypev, ok := visitor.(RecursiveTypeVisitor); ok {
+		ta.recursiveTypeVisitor = recursiveTypev.VisitRecursiveType
+	}
+
+	return ta
+}
+
+// Init delegates to composed InitVisitor
+func (ta TypeVisitorAdapter) Init() {
+	ta.initVisitor()
+}
+
+// VisitScalarType delegates to composed ScalarTypeVisitor
+func (ta TypeVisitorAdapter) VisitScalarType(t reflect.Type) {
+	ta.scalarTypeVisitor(t)
+}
+
+// VisitInterfaceType delegates to composed InterfaceTypeVisitor
+func (ta TypeVisitorAdapter) VisitInterfaceType(t reflect.Type) {
+	ta.interfaceTypeVisitor(t)
+}
+
+// VisitPrePtrType delegates to composed PrePtrTypeVisitor
+func (ta TypeVisitorAdapter) VisitPrePtrType(t reflect.Type) {
+	ta.prePtrTypeVisitor(t)
+}
+
+// VisitPostPtrType delegates to composed PostPtrTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostPtrType(t reflect.Type) {
+	ta.postPtrTypeVisitor(t)
+}
+
+// VisitPreArrayType delegates to composed PreArrayTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreArrayType(length int, t reflect.Type) {
+	ta.preArrayTypeVisitor(length, t)
+}
+
+// VisitPreArrayElemType delegates to composed PreArrayElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreArrayElemType(length int, t reflect.Type) {
+	ta.preArrayElemTypeVisitor(length, t)
+}
+
+// VisitPostArrayElemType delegates to composed PostArrayElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostArrayElemType(length int, t reflect.Type) {
+	ta.postArrayElemTypeVisitor(length, t)
+}
+
+// VisitPostArrayType delegates to composed PostArrayTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostArrayType(length int, t reflect.Type) {
+	ta.postArrayTypeVisitor(length, t)
+}
+
+// VisitPreSliceType delegates to composed PreSliceTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreSliceType(t reflect.Type) {
+	ta.preSliceTypeVisitor(t)
+}
+
+// VisitPreSliceElemType delegates to composed PreSliceElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreSliceElemType(t reflect.Type) {
+	ta.preSliceElemTypeVisitor(t)
+}
+
+// VisitPostSliceElemType delegates to composed PostSliceElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostSliceElemType(t reflect.Type) {
+	ta.postSliceElemTypeVisitor(t)
+}
+
+// VisitPostSliceType delegates to composed PostSliceTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostSliceType(t reflect.Type) {
+	ta.postSliceTypeVisitor(t)
+}
+
+// VisitPreMapType delegates to composed PreMapTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreMapType(t reflect.Type) {
+	ta.preMapTypeVisitor(t)
+}
+
+// VisitPreMapKeyType delegates to composed PreMapKeyTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreMapKeyType(t reflect.Type) {
+	ta.preMapKeyTypeVisitor(t)
+}
+
+// VisitPostMapKeyType delegates to composed PostMapKeyTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostMapKeyType(t reflect.Type) {
+	ta.postMapKeyTypeVisitor(t)
+}
+
+// VisitPreMapElemType delegates to composed PreMapElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreMapElemType(t reflect.Type) {
+	ta.preMapElemTypeVisitor(t)
+}
+
+// VisitPostMapElemType delegates to composed PostMapElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostMapElemType(t reflect.Type) {
+	ta.postMapElemTypeVisitor(t)
+}
+
+// VisitPostMapType delegates to composed PostMapTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostMapType(t reflect.Type) {
+	ta.postMapTypeVisitor(t)
+}
+
+// VisitPreChanType delegates to composed PreChanTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreChanType(t reflect.Type) {
+	ta.preChanTypeVisitor(t)
+}
+
+// VisitPreChanElemType delegates to composed PreChanElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreChanElemType(t reflect.Type) {
+	ta.preChanElemTypeVisitor(t)
+}
+
+// VisitPostChanElemType delegates to composed PostChanElemTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostChanElemType(t reflect.Type) {
+	ta.postChanElemTypeVisitor(t)
+}
+
+// VisitPostChanType delegates to composed PostChanTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostChanType(t reflect.Type) {
+	ta.postChanTypeVisitor(t)
+}
+
+// VisitPreStructType delegates to composed PreStructTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreStructType(numFields int, t reflect.Type) {
+	ta.preStructTypeVisitor(numFields, t)
+}
+
+// VisitPreStructFieldType delegates to composed PreStructFieldTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreStructFieldType(numFields int, index int, f reflect.StructField) {
+	ta.preStructFieldTypeVisitor(numFields, index, f)
+}
+
+// VisitPostStructFieldType delegates to composed PostStructFieldTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostStructFieldType(numFields int, index int, f reflect.StructField) {
+	ta.postStructFieldTypeVisitor(numFields, index, f)
+}
+
+// VisitPostStructType delegates to composed PostStructTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostStructType(numFields int, t reflect.Type) {
+	ta.postStructTypeVisitor(numFields, t)
+}
+
+// VisitPreFuncType delegates to composed PreFuncTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreFuncType(t reflect.Type) {
+	ta.preFuncTypeVisitor(t)
+}
+
+// VisitPreFuncInType delegates to composed PreFuncInTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreFuncInType(numIn int, index int, t reflect.Type) {
+	ta.preFuncInTypeVisitor(numIn, index, t)
+}
+
+// VisitPostFuncInType delegates to composed PostFuncInTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostFuncInType(numIn int, index int, t reflect.Type) {
+	ta.postFuncInTypeVisitor(numIn, index, t)
+}
+
+// VisitPreFuncOutType delegates to composed PreFuncOutTypeVisitor
+func (ta TypeVisitorAdapter) VisitPreFuncOutType(numOut int, index int, t reflect.Type) {
+	ta.preFuncOutTypeVisitor(numOut, index, t)
+}
+
+// VisitPostFuncOutType delegates to composed PostFuncOutTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostFuncOutType(numOut int, index int, t reflect.Type) {
+	ta.postFuncOutTypeVisitor(numOut, index, t)
+}
+
+// VisitPostFuncType delegates to composed PostFuncTypeVisitor
+func (ta TypeVisitorAdapter) VisitPostFuncType(t reflect.Type) {
+	ta.postFuncTypeVisitor(t)
+}
+
+// VisitRecursiveType delegates to composed RecursiveTypeVisitor
+func (ta TypeVisitorAdapter) VisitRecursiveType(t reflect.Type) {
+	ta.recursiveTypeVisitor(t)
+}
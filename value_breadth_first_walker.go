@@ -0,0 +1,342 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueBreadthFirstWalker visits a value in a breadth first (level order) traversal,
+// visiting the top level value, then all of its components, then all of their components, and so on.
+// A walk begins with the Walk method, which accepts a value to walk.
+// A walker instance is reuseable, it can be called many times wth different values and/or different ValueVisitors.
+//
+// The same ValueVisitor methods are called as for a ValueDepthFirstWalker, but in level order the components of a value
+// are visited after the value itself and all other values at the same level. When a ptr, interface, array, slice, map,
+// or struct is visited, all of its pre, index, and post methods are called together, and its components are queued to be
+// visited later. This means the pre methods (eg VisitPreSlice, VisitPreSliceIndex) are meaningful, as they describe the
+// value and each of its components, but the post methods (eg VisitPostSlice, VisitPostSliceIndex) are called before any
+// component is visited, so they cannot be used to combine the results of visiting the components.
+//
+// Example call sequence to visit a *map[string]int = &map[string]int{"foo": 1, "bar": 2}:
+// Walk(&map[string]int{"foo": 1, "bar": 2}, rv)
+// VisitPrePtr(&map[string]int{"foo": 1, "bar": 2})
+// VisitPostPtr(&map[string]int{"foo": 1, "bar": 2})
+// VisitPreMap(2, map[string]int{"foo": 1, "bar": 2})
+// VisitPreMapKeyValue(2, 0, "foo", 1)
+// VisitPreMapKey(2, 0, "foo")
+// VisitPostMapKey(2, 0, "foo")
+// VisitPreMapValue(2, 0, 1)
+// VisitPostMapValue(2, 0, 1)
+// VisitPostMapKeyValue(2, 0, "foo", 1)
+// VisitPreMapKeyValue(2, 1, "bar", 2)
+// VisitPreMapKey(2, 1, "bar")
+// VisitPostMapKey(2, 1, "bar")
+// VisitPreMapValue(2, 1, 2)
+// VisitPostMapValue(2, 1, 2)
+// VisitPostMapKeyValue(2, 1, "bar", 2)
+// VisitPostMap(2, map[string]int{"foo": 1, "bar": 2})
+// VisitString("foo")
+// VisitInt(1)
+// VisitString("bar")
+// VisitInt(2)
+//
+// The options are the same as for a ValueDepthFirstWalker. A pointer, slice, or map that refers back to a value that
+// contains it is one that refers back to a value on the path from the top level value, as level order has no notion of
+// a value still being walked.
+//
+// If the visitor is also PathAware, then the path is that of the value being visited, and for index methods,
+// the path of the array, slice, map, or struct.
+//
+// If the visitor is also a ValueWalkController, then the action methods decide whether the components of a value are
+// queued to be visited. A skipped value is still postvisited, a stopped walk makes no further visits at all.
+type ValueBreadthFirstWalker struct {
+	valueWalkerOptions
+}
+
+// valueBreadthFirstItem is a value queued to be visited, along with its path and the item that queued it
+type valueBreadthFirstItem struct {
+	value  reflect.Value
+	path   ValuePath
+	parent *valueBreadthFirstItem
+}
+
+// NewValueBreadthFirstWalker constructs a ValueBreadthFirstWalker with an optional ValueVisitor
+func NewValueBreadthFirstWalker(visitor ...ValueVisitor) ValueBreadthFirstWalker {
+	var vis ValueVisitor
+	if len(visitor) > 0 {
+		vis = visitor[0]
+	}
+
+	return ValueBreadthFirstWalker{valueWalkerOptions{visitor: vis}}
+}
+
+// Walk walks the given value in a breadth-first traversal.
+// The value passed can be a reflect.Value wrapper or a plain value.
+// The Walk can be invoked multiple times with different values, as each walk begins by calling the Init() method the visitor given in the costructor.
+// There is no return result from the walk. Instead, the visitor is expected to have a Result() method that returns the appropriate type.
+func (w ValueBreadthFirstWalker) Walk(val interface{}) {
+	w.walk(val, newValueWalkState(w.visitor))
+}
+
+// WalkE is the same as Walk, except that any panic that occurs during the walk is recovered and returned as a WalkError.
+// See ValueDepthFirstWalker.WalkE.
+func (w ValueBreadthFirstWalker) WalkE(val interface{}) (err error) {
+	st := newValueWalkState(w.visitor)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoveredWalkError(st.path, recovered)
+		}
+	}()
+
+	w.walk(val, st)
+	return
+}
+
+// walk initializes the visitor, and walks the given value
+func (w ValueBreadthFirstWalker) walk(val interface{}, st *valueWalkState) {
+	st.initVisitor()
+
+	queue := []*valueBreadthFirstItem{{value: GetReflectValueOf(val)}}
+	st.nodes++
+
+	for (len(queue) > 0) && !st.stopped {
+		item := queue[0]
+		queue[0] = nil
+		queue = queue[1:]
+
+		st.path = item.path
+		queue = w.dispatch(item, queue, st)
+	}
+}
+
+// enter returns true if a pointer, slice, or map should be visited.
+// If it refers back to a value on the path of the item, or is shared and shared references are tracked,
+// then the back reference is handled according to the back reference mode and false is returned.
+func (w ValueBreadthFirstWalker) enter(item *valueBreadthFirstItem, st *valueWalkState) bool {
+	ref, trackable := refOf(item.value)
+	if !trackable {
+		return true
+	}
+
+	for ancestor := item.parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestorRef, ancestorTrackable := refOf(ancestor.value); ancestorTrackable && (ancestorRef == ref) {
+			w.backReference("ValueBreadthFirstWalker", item.value, ancestor.path, st)
+			return false
+		}
+	}
+
+	if walked, exists := st.walked[ref]; exists {
+		if w.sharedReferences {
+			w.backReference("ValueBreadthFirstWalker", item.value, walked.path, st)
+			return false
+		}
+	} else {
+		st.walked[ref] = &walkedRef{path: item.path.Copy()}
+	}
+
+	return true
+}
+
+// child returns an item for a component of the given item, counting it as a node
+func (w ValueBreadthFirstWalker) child(item *valueBreadthFirstItem, v reflect.Value, e ValuePathElement, st *valueWalkState) *valueBreadthFirstItem {
+	st.nodes++
+
+	path := item.path.Copy()
+	path.push(e)
+
+	return &valueBreadthFirstItem{value: v, path: path, parent: item}
+}
+
+// Dispatch executes the appropriate visitor methods for a value based on the type,
+// and returns the queue with the components of the value appended
+func (w ValueBreadthFirstWalker) dispatch(item *valueBreadthFirstItem, queue []*valueBreadthFirstItem, st *valueWalkState) []*valueBreadthFirstItem {
+	v := item.value
+
+	switch v.Kind() {
+	case reflect.Bool:
+		w.visitor.VisitBool(v.Bool())
+
+	case reflect.Int:
+		w.visitor.VisitInt(int(v.Int()))
+
+	case reflect.Int8:
+		w.visitor.VisitInt8(int8(v.Int()))
+
+	case reflect.Int16:
+		w.visitor.VisitInt16(int16(v.Int()))
+
+	case reflect.Int32:
+		w.visitor.VisitInt32(int32(v.Int()))
+
+	case reflect.Int64:
+		w.visitor.VisitInt64(v.Int())
+
+	case reflect.Uint:
+		w.visitor.VisitUint(uint(v.Uint()))
+
+	case reflect.Uint8:
+		w.visitor.VisitUint8(uint8(v.Uint()))
+
+	case reflect.Uint16:
+		w.visitor.VisitUint16(uint16(v.Uint()))
+
+	case reflect.Uint32:
+		w.visitor.VisitUint32(uint32(v.Uint()))
+
+	case reflect.Uint64:
+		w.visitor.VisitUint64(v.Uint())
+
+	case reflect.Float32:
+		w.visitor.VisitFloat32(float32(v.Float()))
+
+	case reflect.Float64:
+		w.visitor.VisitFloat64(v.Float())
+
+	case reflect.Complex64:
+		w.visitor.VisitComplex64(complex64(v.Complex()))
+
+	case reflect.Complex128:
+		w.visitor.VisitComplex128(v.Complex())
+
+	case reflect.String:
+		w.visitor.VisitString(v.String())
+
+	case reflect.Chan:
+		w.visitor.VisitChan(v)
+
+	case reflect.Func:
+		w.visitor.VisitFunc(v)
+
+	case reflect.Invalid:
+		w.visitor.VisitNil(v)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			w.visitor.VisitNil(v)
+		} else if w.enter(item, st) {
+			if st.prePtr(v) && w.walkComponent(reflect.Ptr, 0, w.componentLimit(reflect.Ptr, 1, st), 1, st) {
+				queue = append(queue, w.child(item, v.Elem(), ValuePathElement{Kind: PathDeref}, st))
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostPtr(v)
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			w.visitor.VisitNil(v)
+		} else {
+			if st.preInterface(v) && w.walkComponent(reflect.Interface, 0, w.componentLimit(reflect.Interface, 1, st), 1, st) {
+				queue = append(queue, w.child(item, v.Elem(), ValuePathElement{Kind: PathInterface}, st))
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostInterface(v)
+		}
+
+	case reflect.Array:
+		{
+			n := v.Len()
+			if st.preArray(n, v) {
+				limit := w.componentLimit(reflect.Array, n, st)
+				for i := 0; w.walkComponent(reflect.Array, i, limit, n, st); i++ {
+					e := v.Index(i)
+
+					w.visitor.VisitPreArrayIndex(n, i, e)
+					queue = append(queue, w.child(item, e, ValuePathElement{Kind: PathIndex, Index: i}, st))
+					w.visitor.VisitPostArrayIndex(n, i, e)
+				}
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostArray(n, v)
+		}
+
+	case reflect.Slice:
+		if w.enter(item, st) {
+			n := v.Len()
+			if st.preSlice(n, v) {
+				limit := w.componentLimit(reflect.Slice, n, st)
+				for i := 0; w.walkComponent(reflect.Slice, i, limit, n, st); i++ {
+					e := v.Index(i)
+
+					w.visitor.VisitPreSliceIndex(n, i, e)
+					queue = append(queue, w.child(item, e, ValuePathElement{Kind: PathIndex, Index: i}, st))
+					w.visitor.VisitPostSliceIndex(n, i, e)
+				}
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostSlice(n, v)
+		}
+
+	case reflect.Map:
+		if w.enter(item, st) {
+			n := v.Len()
+			if st.preMap(n, v) {
+				iter, limit := w.mapIter(v), w.componentLimit(reflect.Map, n, st)
+				for i := 0; w.walkComponent(reflect.Map, i, limit, n, st); i++ {
+					mk, mv, _ := iter()
+
+					w.visitor.VisitPreMapKeyValue(n, i, mk, mv)
+
+					w.visitor.VisitPreMapKey(n, i, mk)
+					queue = append(queue, w.child(item, mk, ValuePathElement{Kind: PathMapKey, Index: i, Key: mk}, st))
+					w.visitor.VisitPostMapKey(n, i, mk)
+
+					w.visitor.VisitPreMapValue(n, i, mv)
+					queue = append(queue, w.child(item, mv, ValuePathElement{Kind: PathMapValue, Index: i, Key: mk}, st))
+					w.visitor.VisitPostMapValue(n, i, mv)
+
+					w.visitor.VisitPostMapKeyValue(n, i, mk, mv)
+				}
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostMap(n, v)
+		}
+
+	case reflect.Struct:
+		{
+			fields := w.structFields(v)
+			n := len(fields)
+			if st.preStruct(n, v) {
+				limit := w.componentLimit(reflect.Struct, n, st)
+				for i := 0; w.walkComponent(reflect.Struct, i, limit, n, st); i++ {
+					sf, sv := fields[i].field, fields[i].value
+
+					w.visitor.VisitPreStructFieldValue(n, i, sf, sv)
+					queue = append(queue, w.child(item, sv, ValuePathElement{Kind: PathField, Index: i, Name: sf.Name}, st))
+					w.visitor.VisitPostStructFieldValue(n, i, sf, sv)
+				}
+			}
+
+			if st.stopped {
+				return queue
+			}
+
+			w.visitor.VisitPostStruct(n, v)
+		}
+
+	default:
+		panic(fmt.Errorf("goreflect.ValueBreadthFirstWalker.dispatch: value of kind %s cannot be visited", v.Kind()))
+	}
+
+	return queue
+}
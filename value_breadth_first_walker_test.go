@@ -0,0 +1,275 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueBreadthFirstWalkerOrder(t *testing.T) {
+	var (
+		visits []string
+		d      = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			switch m {
+			case "VisitInt", "VisitString":
+				visits = append(visits, fmt.Sprintf("%s(%v)", m, a[0].Interface()))
+			case "VisitPreSlice", "VisitPostSlice", "VisitPreStruct", "VisitPostStruct":
+				visits = append(visits, fmt.Sprintf("%s(%d)", m, a[0].Interface()))
+			case "VisitPreSliceIndex", "VisitPostSliceIndex":
+				visits = append(visits, fmt.Sprintf("%s(%d)", m, a[1].Interface()))
+			default:
+				visits = append(visits, m)
+			}
+		})
+		w = NewValueBreadthFirstWalker(d)
+	)
+
+	// Each level is visited before the next, with pre, index, and post methods called together
+	w.Walk([][]int{{1, 2}, {3}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSliceIndex(0)",
+			"VisitPostSliceIndex(0)",
+			"VisitPreSliceIndex(1)",
+			"VisitPostSliceIndex(1)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitPreSliceIndex(0)",
+			"VisitPostSliceIndex(0)",
+			"VisitPreSliceIndex(1)",
+			"VisitPostSliceIndex(1)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(1)",
+			"VisitPreSliceIndex(0)",
+			"VisitPostSliceIndex(0)",
+			"VisitPostSlice(1)",
+			"VisitInt(1)",
+			"VisitInt(2)",
+			"VisitInt(3)",
+		},
+		visits,
+	)
+
+	visits = nil
+	w.Walk(&walkerTestNode{Name: "first", Next: &walkerTestNode{Name: "second"}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPrePtr",
+			"VisitPostPtr",
+			"VisitPreStruct(2)",
+			"VisitPreStructFieldValue",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitPostStructFieldValue",
+			"VisitPostStruct(2)",
+			"VisitString(first)",
+			"VisitPrePtr",
+			"VisitPostPtr",
+			"VisitPreStruct(2)",
+			"VisitPreStructFieldValue",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitPostStructFieldValue",
+			"VisitPostStruct(2)",
+			"VisitString(second)",
+			"VisitNil",
+		},
+		visits,
+	)
+}
+
+func TestValueBreadthFirstWalkerPath(t *testing.T) {
+	var (
+		v = &walkerTestValidator{}
+		w = NewValueBreadthFirstWalker(NewValueVisitorAdapter(v))
+	)
+
+	// The shallowest invalid value is found first, regardless of the order of fields
+	w.Walk(struct {
+		Deep    [][]string
+		Shallow []string
+	}{
+		Deep:    [][]string{{"a", ""}},
+		Shallow: []string{"b", ""},
+	})
+	assert.Equal(
+		t,
+		[]string{
+			`.Shallow[1]`,
+			`.Deep[0][1]`,
+		},
+		v.invalid,
+	)
+
+	w.WithSortedMapKeys()
+	w.Walk(map[string]*walkerTestItem{"b": {Name: ""}, "a": nil})
+	assert.Equal(
+		t,
+		[]string{
+			`["a"]`,
+			`["b"].Name`,
+			`["b"].Price`,
+		},
+		v.invalid,
+	)
+}
+
+func TestValueBreadthFirstWalkerBackReferences(t *testing.T) {
+	var (
+		backRefs []string
+		d        = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			if m == "VisitBackReference" {
+				backRefs = append(backRefs, a[1].Interface().(ValuePath).String())
+			}
+		})
+		w = NewValueBreadthFirstWalker(d)
+	)
+
+	// Linked list whose last node points back to the first
+	first := &walkerTestNode{Name: "first"}
+	second := &walkerTestNode{Name: "second", Next: first}
+	first.Next = second
+
+	// Panic by default, describing where the cycle occurs
+	func() {
+		defer func() {
+			assert.Equal(
+				t,
+				fmt.Errorf(`goreflect.ValueBreadthFirstWalker.dispatch: value of type *goreflect.walkerTestNode at ".Next.Next" refers back to ""`),
+				recover(),
+			)
+		}()
+
+		w.Walk(first)
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Notify
+	w.WithBackReferenceMode(BackReferenceNotify)
+	w.Walk(first)
+	assert.Equal(t, []string{""}, backRefs)
+
+	// Shared references are walked each time by default, or handled as back references
+	backRefs = nil
+	shared := &walkerTestNode{Name: "shared"}
+	w.Walk([]*walkerTestNode{shared, shared})
+	assert.Equal(t, []string(nil), backRefs)
+
+	w.WithSharedReferences()
+	w.Walk([]*walkerTestNode{shared, shared})
+	assert.Equal(t, []string{"[0]"}, backRefs)
+}
+
+func TestValueBreadthFirstWalkerWalkActions(t *testing.T) {
+	var (
+		c = &walkerTestController{}
+		w = NewValueBreadthFirstWalker(NewValueVisitorAdapter(c))
+	)
+
+	// Skip children of long slices, but still postvisit them
+	w.Walk([][]int{{1, 2}, {3, 4, 5}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitInt(1)",
+			"VisitInt(2)",
+		},
+		c.visits,
+	)
+
+	// Stop at first map, without any further visits
+	c.visits = nil
+	w.Walk([]interface{}{map[int]int{2: 3}, 1})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitPreMapAction",
+		},
+		c.visits,
+	)
+}
+
+func TestValueBreadthFirstWalkerWalkE(t *testing.T) {
+	var (
+		v = &walkerTestErrorVisitor{}
+		w = NewValueBreadthFirstWalker(NewValueVisitorAdapter(v))
+	)
+
+	assert.Nil(t, w.WalkE([]string{"a", "b"}))
+
+	err := w.WalkE([][]string{{"a", ""}, {""}})
+	assert.Equal(t, `goreflect: walk failed at "[0][1]": empty string`, err.Error())
+	assert.Equal(t, errWalkerTestEmpty, err.(WalkError).Err)
+}
+
+func TestValueBreadthFirstWalkerStructFields(t *testing.T) {
+	var (
+		v = &walkerTestValidator{}
+		w = NewValueBreadthFirstWalker(NewValueVisitorAdapter(v))
+	)
+
+	w.WithStructTag("reflect")
+	w.Walk(struct {
+		Val   walkerTestTagged `reflect:"val"`
+		Empty string           `reflect:"-"`
+	}{})
+	assert.Equal(t, []string{".val.Name"}, v.invalid)
+}
+
+func TestValueBreadthFirstWalkerLimits(t *testing.T) {
+	var (
+		c = &walkerTestController{}
+		w = NewValueBreadthFirstWalker(NewValueVisitorAdapter(c))
+	)
+
+	// Total nodes are counted level by level, so the shallowest values are walked
+	w.WithMaxNodes(4)
+	w.Walk([][]int{{1, 2}, {3, 4}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitPostSlice(2)",
+			"VisitInt(1)",
+		},
+		c.visits,
+	)
+
+	// Truncation events are delivered when the value is visited, before its components
+	var visits []string
+	w.WithoutMaxNodes()
+	w.WithMaxElements(1)
+	w.WithVisitor(NewValueVisitorProxy(func(m string, a []reflect.Value) {
+		switch m {
+		case "VisitInt", "VisitTruncated":
+			visits = append(visits, fmt.Sprintf("%s(%v)", m, a[len(a)-1].Interface()))
+		}
+	}))
+	w.Walk([][]int{{1, 2}, {3}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitTruncated(1)",
+			"VisitTruncated(1)",
+			"VisitInt(1)",
+		},
+		visits,
+	)
+}
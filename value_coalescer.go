@@ -397,3 +397,23 @@ func (c ValueCoalescer) VisitPostArray(length int, val reflect.Value) {
 		c.visitor.VisitPostArray(length, val)
 	}
 }
+
+// VisitNil passes through a nil value
+func (c ValueCoalescer) VisitNil(val reflect.Value) {
+	c.visitor.VisitNil(val)
+}
+
+// VisitPreInterface passes through an interface value
+func (c ValueCoalescer) VisitPreInterface(val reflect.Value) {
+	c.visitor.VisitPreInterface(val)
+}
+
+// VisitPostInterface passes through an interface value
+func (c ValueCoalescer) VisitPostInterface(val reflect.Value) {
+	c.visitor.VisitPostInterface(val)
+}
+
+// VisitTruncated passes through truncated components
+func (c ValueCoalescer) VisitTruncated(kind reflect.Kind, remaining int) {
+	c.visitor.VisitTruncated(kind, remaining)
+}
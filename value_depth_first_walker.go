@@ -1,6 +1,7 @@
 package goreflect
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -32,11 +33,31 @@ import (
 // VisitPostMap(2, map[string]int{"foo": 1, "bar": 2})
 // VisitPostPtr(&map[string]int{"foo": 1, "bar": 2})
 //
-// Note that it is not usually desirable to walk the fields of a struct.
-// By default, struct fields are not walked, the WithStructFields method causes the fields to be walked.
+// By default, all fields of a struct are walked, including unexported fields.
+// The WithExportedFieldsOnly, WithStructTag, and WithStructFieldFilter methods select which struct fields are walked,
+// and the length and indexes passed to struct visitor methods count only the selected fields.
+// The WithPromotedFields method causes the fields of embedded structs to be walked as fields of the embedding struct.
+//
+// A pointer, slice, or map that refers back to a value that contains it (a cycle) is not walked again,
+// as doing so would never terminate. The WithBackReferenceMode method determines what happens instead.
+// By default, a pointer, slice, or map that is shared by different parts of the value is walked each time it is encountered.
+// The WithSharedReferences method causes shared references to be handled the same way as cycles.
+//
+// By default, map keys are walked in the order Go iterates them, which differs each time a map is walked.
+// The WithSortedMapKeys and WithMapKeyComparator methods cause map keys to be walked in sorted order.
+//
+// The WithMaxDepth, WithMaxElements, and WithMaxNodes methods limit how much of a value is walked.
+// When a limit stops the components of a value from being walked, VisitTruncated is called with the number of
+// components not walked, before the value is postvisited.
+//
+// If the visitor is also PathAware (as a ValueVisitorAdapter is), then it is given the path of the value being visited.
+// If the visitor is also ContextAware (as a ValueVisitorAdapter is), then it is given the context of the walk (see WalkContext).
+//
+// If the visitor is also a ValueWalkController (as a ValueVisitorAdapter is), then ptr, interface, array, slice, map,
+// and struct values are previsited with the action methods, which decide whether to walk the components of the value,
+// skip them, or stop the walk. A skipped value is still postvisited, a stopped walk makes no further visits at all.
 type ValueDepthFirstWalker struct {
-	visitor          ValueVisitor
-	walkStructFields bool
+	valueWalkerOptions
 }
 
 // NewValueDepthFirstWalker constructs a ValueDepthFirstWalker with an optional ValueVisitor
@@ -46,35 +67,99 @@ func NewValueDepthFirstWalker(visitor ...ValueVisitor) ValueDepthFirstWalker {
 		vis = visitor[0]
 	}
 
-	return ValueDepthFirstWalker{visitor: vis}
+	return ValueDepthFirstWalker{valueWalkerOptions{visitor: vis}}
 }
 
-// WithVisitor sets the visitor to walk
-func (w *ValueDepthFirstWalker) WithVisitor(visitor ValueVisitor) {
-	w.visitor = visitor
+// Walk walks the given value in a depth-first traversal.
+// The value passed can be a reflect.Value wrapper or a plain value.
+// The Walk can be invoked multiple times with different values, as each walk begins by calling the Init() method the visitor given in the costructor.
+// There is no return result from the walk. Instead, the visitor is expected to have a Result() method that returns the appropriate type.
+func (w ValueDepthFirstWalker) Walk(val interface{}) {
+	w.walk(val, newValueWalkState(w.visitor))
 }
 
-// WithStructFields sets the flag to walk struct fields
-func (w *ValueDepthFirstWalker) WithStructFields() {
-	w.walkStructFields = true
+// WalkE is the same as Walk, except that any panic that occurs during the walk is recovered and returned as a WalkError.
+// A visitor whose methods return errors (see ValueVisitorAdapter) aborts the walk with the first error returned.
+// Visitors that signal failure by panicking are also handled, the panic value is the underlying error if it is an error,
+// otherwise the underlying error describes the panic value.
+// Returns nil if the walk completes without a panic.
+func (w ValueDepthFirstWalker) WalkE(val interface{}) (err error) {
+	st := newValueWalkState(w.visitor)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoveredWalkError(st.path, recovered)
+		}
+	}()
+
+	w.walk(val, st)
+	return
 }
 
-// WithoutStructFields clears the flag to walk struct fields
-func (w *ValueDepthFirstWalker) WithoutStructFields() {
-	w.walkStructFields = false
+// WalkContext is the same as WalkE, except that the walk is stopped if the context is cancelled.
+// The context is checked periodically rather than for every value, so a few more values may be visited after cancellation.
+// If the visitor is also ContextAware, it is given the context, so that it can also stop when the context is cancelled.
+// Returns ctx.Err() if the walk was stopped by the context.
+func (w ValueDepthFirstWalker) WalkContext(ctx context.Context, val interface{}) (err error) {
+	st := newValueWalkState(w.visitor)
+	st.ctx = ctx
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoveredWalkError(st.path, recovered)
+		}
+	}()
+
+	w.walk(val, st)
+	return st.err
 }
 
-// Walk walks the given value in a depth-first traversal.
-// The value passed can be a reflect.Value wrapper or a plain value.
-// The Walk can be invoked multiple times with different values, as each walk begins by calling the Init() method the visitor given in the costructor.
-// There is no return result from the walk. Instead, the visitor is expected to have a Result() method that returns the appropriate type.
-func (w ValueDepthFirstWalker) Walk(val interface{}) {
-	w.visitor.Init()
-	w.dispatch(GetReflectValueOf(val))
+// walk initializes the visitor, and walks the given value
+func (w ValueDepthFirstWalker) walk(val interface{}, st *valueWalkState) {
+	st.initVisitor()
+
+	w.dispatch(GetReflectValueOf(val), st)
+}
+
+// enter records that a pointer, slice, or map is about to be walked.
+// If it has already been walked and is still being walked, or is shared and shared references are tracked,
+// then the back reference is handled according to the back reference mode and false is returned.
+// Otherwise, true is returned, and leave must be called after the value is walked.
+func (w ValueDepthFirstWalker) enter(v reflect.Value, st *valueWalkState) bool {
+	ref, trackable := refOf(v)
+	if !trackable {
+		return true
+	}
+
+	walked, exists := st.walked[ref]
+	if exists && (walked.walking || w.sharedReferences) {
+		w.backReference("ValueDepthFirstWalker", v, walked.path, st)
+		return false
+	}
+
+	if exists {
+		walked.walking = true
+	} else {
+		st.walked[ref] = &walkedRef{path: st.path.Copy(), walking: true}
+	}
+
+	return true
+}
+
+// leave records that a pointer, slice, or map that enter returned true for is no longer being walked
+func (w ValueDepthFirstWalker) leave(v reflect.Value, st *valueWalkState) {
+	if ref, trackable := refOf(v); trackable {
+		st.walked[ref].walking = false
+	}
 }
 
 // Dispatch executes the appropriate visitor methods for a value based on the type
-func (w ValueDepthFirstWalker) dispatch(v reflect.Value) {
+func (w ValueDepthFirstWalker) dispatch(v reflect.Value, st *valueWalkState) {
+	st.nodes++
+	if st.cancelled() {
+		return
+	}
+
 	switch v.Kind() {
 	case reflect.Bool:
 		w.visitor.VisitBool(v.Bool())
@@ -130,77 +215,169 @@ func (w ValueDepthFirstWalker) dispatch(v reflect.Value) {
 	case reflect.Func:
 		w.visitor.VisitFunc(v)
 
+	case reflect.Invalid:
+		w.visitor.VisitNil(v)
+
 	case reflect.Ptr:
-		w.visitor.VisitPrePtr(v)
-		w.dispatch(v.Elem())
-		w.visitor.VisitPostPtr(v)
+		if v.IsNil() {
+			w.visitor.VisitNil(v)
+		} else if w.enter(v, st) {
+			if st.prePtr(v) && w.walkComponent(reflect.Ptr, 0, w.componentLimit(reflect.Ptr, 1, st), 1, st) {
+				st.path.push(ValuePathElement{Kind: PathDeref})
+				w.dispatch(v.Elem(), st)
+				st.path.pop()
+			}
+
+			if st.stopped {
+				return
+			}
+
+			w.visitor.VisitPostPtr(v)
+			w.leave(v, st)
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			w.visitor.VisitNil(v)
+		} else {
+			if st.preInterface(v) && w.walkComponent(reflect.Interface, 0, w.componentLimit(reflect.Interface, 1, st), 1, st) {
+				st.path.push(ValuePathElement{Kind: PathInterface})
+				w.dispatch(v.Elem(), st)
+				st.path.pop()
+			}
+
+			if st.stopped {
+				return
+			}
+
+			w.visitor.VisitPostInterface(v)
+		}
 
 	case reflect.Array:
 		{
 			n := v.Len()
-			w.visitor.VisitPreArray(n, v)
-
-			for i := 0; i < n; i++ {
-				e := v.Index(i)
+			if st.preArray(n, v) {
+				limit := w.componentLimit(reflect.Array, n, st)
+				for i := 0; w.walkComponent(reflect.Array, i, limit, n, st); i++ {
+					e := v.Index(i)
+
+					w.visitor.VisitPreArrayIndex(n, i, e)
+					st.path.push(ValuePathElement{Kind: PathIndex, Index: i})
+					w.dispatch(e, st)
+					st.path.pop()
+
+					if st.stopped {
+						return
+					}
+
+					w.visitor.VisitPostArrayIndex(n, i, e)
+				}
+			}
 
-				w.visitor.VisitPreArrayIndex(n, i, e)
-				w.dispatch(e)
-				w.visitor.VisitPostArrayIndex(n, i, e)
+			if st.stopped {
+				return
 			}
 
 			w.visitor.VisitPostArray(n, v)
 		}
 
 	case reflect.Slice:
-		{
+		if w.enter(v, st) {
 			n := v.Len()
-			w.visitor.VisitPreSlice(n, v)
-
-			for i := 0; i < n; i++ {
-				e := v.Index(i)
+			if st.preSlice(n, v) {
+				limit := w.componentLimit(reflect.Slice, n, st)
+				for i := 0; w.walkComponent(reflect.Slice, i, limit, n, st); i++ {
+					e := v.Index(i)
+
+					w.visitor.VisitPreSliceIndex(n, i, e)
+					st.path.push(ValuePathElement{Kind: PathIndex, Index: i})
+					w.dispatch(e, st)
+					st.path.pop()
+
+					if st.stopped {
+						return
+					}
+
+					w.visitor.VisitPostSliceIndex(n, i, e)
+				}
+			}
 
-				w.visitor.VisitPreSliceIndex(n, i, e)
-				w.dispatch(e)
-				w.visitor.VisitPostSliceIndex(n, i, e)
+			if st.stopped {
+				return
 			}
 
 			w.visitor.VisitPostSlice(n, v)
+			w.leave(v, st)
 		}
 
 	case reflect.Map:
-		{
+		if w.enter(v, st) {
 			n := v.Len()
-			w.visitor.VisitPreMap(n, v)
+			if st.preMap(n, v) {
+				iter, limit := w.mapIter(v), w.componentLimit(reflect.Map, n, st)
+				for i := 0; w.walkComponent(reflect.Map, i, limit, n, st); i++ {
+					mk, mv, _ := iter()
+
+					w.visitor.VisitPreMapKeyValue(n, i, mk, mv)
 
-			for i, iter := 0, v.MapRange(); iter.Next(); i++ {
-				mk, mv := iter.Key(), iter.Value()
-				w.visitor.VisitPreMapKeyValue(n, i, mk, mv)
+					w.visitor.VisitPreMapKey(n, i, mk)
+					st.path.push(ValuePathElement{Kind: PathMapKey, Index: i, Key: mk})
+					w.dispatch(mk, st)
+					st.path.pop()
 
-				w.visitor.VisitPreMapKey(n, i, mk)
-				w.dispatch(mk)
-				w.visitor.VisitPostMapKey(n, i, mk)
+					if st.stopped {
+						return
+					}
 
-				w.visitor.VisitPreMapValue(n, i, mv)
-				w.dispatch(mv)
-				w.visitor.VisitPostMapValue(n, i, mv)
+					w.visitor.VisitPostMapKey(n, i, mk)
 
-				w.visitor.VisitPostMapKeyValue(n, i, mk, mv)
+					w.visitor.VisitPreMapValue(n, i, mv)
+					st.path.push(ValuePathElement{Kind: PathMapValue, Index: i, Key: mk})
+					w.dispatch(mv, st)
+					st.path.pop()
+
+					if st.stopped {
+						return
+					}
+
+					w.visitor.VisitPostMapValue(n, i, mv)
+
+					w.visitor.VisitPostMapKeyValue(n, i, mk, mv)
+				}
+			}
+
+			if st.stopped {
+				return
 			}
 
 			w.visitor.VisitPostMap(n, v)
+			w.leave(v, st)
 		}
 
 	case reflect.Struct:
 		{
-			n := v.NumField()
-			w.visitor.VisitPreStruct(n, v)
-
-			for i := 0; i < n; i++ {
-				sf, sv := v.Type().Field(i), v.Field(i)
+			fields := w.structFields(v)
+			n := len(fields)
+			if st.preStruct(n, v) {
+				limit := w.componentLimit(reflect.Struct, n, st)
+				for i := 0; w.walkComponent(reflect.Struct, i, limit, n, st); i++ {
+					sf, sv := fields[i].field, fields[i].value
+
+					w.visitor.VisitPreStructFieldValue(n, i, sf, sv)
+					st.path.push(ValuePathElement{Kind: PathField, Index: i, Name: sf.Name})
+					w.dispatch(sv, st)
+					st.path.pop()
+
+					if st.stopped {
+						return
+					}
+
+					w.visitor.VisitPostStructFieldValue(n, i, sf, sv)
+				}
+			}
 
-				w.visitor.VisitPreStructFieldValue(n, i, sf, sv)
-				w.dispatch(sv)
-				w.visitor.VisitPostStructFieldValue(n, i, sf, sv)
+			if st.stopped {
+				return
 			}
 
 			w.visitor.VisitPostStruct(n, v)
@@ -0,0 +1,853 @@
+package goreflect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type walkerTestNode struct {
+	Name string
+	Next *walkerTestNode
+}
+
+type walkerTestPtr *walkerTestPtr
+
+type walkerTestSlice []walkerTestSlice
+
+type walkerTestMap map[string]walkerTestMap
+
+func TestValueDepthFirstWalkerBackReferences(t *testing.T) {
+	var (
+		methodNames []string
+		backRefs    []string
+		d           = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			methodNames = append(methodNames, m)
+			if m == "VisitBackReference" {
+				backRefs = append(backRefs, a[1].Interface().(ValuePath).String())
+			}
+		})
+		w = NewValueDepthFirstWalker(d)
+	)
+
+	clear := func() {
+		methodNames = []string{}
+		backRefs = []string{}
+	}
+
+	// Linked list whose last node points back to the first
+	first := &walkerTestNode{Name: "first"}
+	second := &walkerTestNode{Name: "second", Next: first}
+	first.Next = second
+
+	// Panic by default, describing where the cycle occurs
+	func() {
+		defer func() {
+			assert.Equal(
+				t,
+				fmt.Errorf(`goreflect.ValueDepthFirstWalker.dispatch: value of type *goreflect.walkerTestNode at ".Next.Next" refers back to ""`),
+				recover(),
+			)
+		}()
+
+		w.Walk(first)
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Skip silently
+	clear()
+	w.WithBackReferenceMode(BackReferenceSkip)
+	w.Walk(first)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPrePtr",
+			"VisitPreStruct",
+			"VisitPreStructFieldValue",
+			"VisitString",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitPrePtr",
+			"VisitPreStruct",
+			"VisitPreStructFieldValue",
+			"VisitString",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitPostStructFieldValue",
+			"VisitPostStruct",
+			"VisitPostPtr",
+			"VisitPostStructFieldValue",
+			"VisitPostStruct",
+			"VisitPostPtr",
+		},
+		methodNames,
+	)
+	assert.Equal(t, []string{}, backRefs)
+
+	// Notify with original path
+	clear()
+	w.WithBackReferenceMode(BackReferenceNotify)
+	w.Walk(first)
+	assert.Equal(t, []string{""}, backRefs)
+
+	clear()
+	w.Walk(&first)
+	assert.Equal(t, []string{""}, backRefs)
+
+	// Pointer that points to itself
+	var p walkerTestPtr
+	p = &p
+	clear()
+	w.Walk(p)
+	assert.Equal(t, []string{"VisitPrePtr", "VisitBackReference", "VisitPostPtr"}, methodNames)
+	assert.Equal(t, []string{""}, backRefs)
+
+	// Slice that contains itself
+	s := walkerTestSlice{nil, nil}
+	s[1] = s
+	clear()
+	w.Walk(s)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice",
+			"VisitPreSliceIndex",
+			"VisitPreSlice",
+			"VisitPostSlice",
+			"VisitPostSliceIndex",
+			"VisitPreSliceIndex",
+			"VisitBackReference",
+			"VisitPostSliceIndex",
+			"VisitPostSlice",
+		},
+		methodNames,
+	)
+	assert.Equal(t, []string{""}, backRefs)
+
+	// Map that contains itself
+	m := walkerTestMap{}
+	m["self"] = m
+	clear()
+	w.Walk(m)
+	assert.Equal(t, []string{""}, backRefs)
+
+	// Shared references are walked each time by default
+	i := 1
+	shared := []*int{&i, &i}
+	clear()
+	w.Walk(shared)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice",
+			"VisitPreSliceIndex",
+			"VisitPrePtr",
+			"VisitInt",
+			"VisitPostPtr",
+			"VisitPostSliceIndex",
+			"VisitPreSliceIndex",
+			"VisitPrePtr",
+			"VisitInt",
+			"VisitPostPtr",
+			"VisitPostSliceIndex",
+			"VisitPostSlice",
+		},
+		methodNames,
+	)
+
+	// Shared references can be handled as back references
+	clear()
+	w.WithSharedReferences()
+	w.Walk(shared)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice",
+			"VisitPreSliceIndex",
+			"VisitPrePtr",
+			"VisitInt",
+			"VisitPostPtr",
+			"VisitPostSliceIndex",
+			"VisitPreSliceIndex",
+			"VisitBackReference",
+			"VisitPostSliceIndex",
+			"VisitPostSlice",
+		},
+		methodNames,
+	)
+	assert.Equal(t, []string{"[0]"}, backRefs)
+
+	clear()
+	w.WithoutSharedReferences()
+	w.Walk(shared)
+	assert.Equal(t, []string{}, backRefs)
+}
+
+func TestValuePath(t *testing.T) {
+	var path ValuePath
+	assert.Equal(t, "", path.String())
+	assert.Equal(t, 0, path.Len())
+
+	path.push(ValuePathElement{Kind: PathField, Index: 0, Name: "Orders"})
+	path.push(ValuePathElement{Kind: PathIndex, Index: 3})
+	path.push(ValuePathElement{Kind: PathDeref})
+	path.push(ValuePathElement{Kind: PathField, Index: 1, Name: "Items"})
+	path.push(ValuePathElement{Kind: PathMapValue, Index: 0, Key: reflect.ValueOf("sku")})
+	path.push(ValuePathElement{Kind: PathField, Index: 2, Name: "Price"})
+	assert.Equal(t, `.Orders[3].Items["sku"].Price`, path.String())
+	assert.Equal(t, 6, path.Len())
+
+	pathCopy := path.Copy()
+	path.pop()
+	path.pop()
+	path.push(ValuePathElement{Kind: PathMapKey, Index: 0, Key: reflect.ValueOf(5)})
+	assert.Equal(t, `.Orders[3].Items{5}`, path.String())
+	assert.Equal(t, `.Orders[3].Items["sku"].Price`, pathCopy.String())
+	assert.Equal(t, PathDeref, pathCopy.Elements()[2].Kind)
+
+	// Keys of an interface type are represented by the value they contain
+	var (
+		keys      = reflect.ValueOf(map[interface{}]int{"1": 1}).MapKeys()
+		intKeys   = reflect.ValueOf(map[interface{}]int{1: 1}).MapKeys()
+		nilKeys   = reflect.ValueOf(map[interface{}]int{nil: 1}).MapKeys()
+		ifacePath ValuePath
+	)
+	assert.Equal(t, reflect.Interface, keys[0].Kind())
+
+	ifacePath.push(ValuePathElement{Kind: PathMapValue, Key: keys[0]})
+	assert.Equal(t, `["1"]`, ifacePath.String())
+	ifacePath.pop()
+	ifacePath.push(ValuePathElement{Kind: PathMapValue, Key: intKeys[0]})
+	assert.Equal(t, `[1]`, ifacePath.String())
+	ifacePath.pop()
+	ifacePath.push(ValuePathElement{Kind: PathMapKey, Key: nilKeys[0]})
+	assert.Equal(t, `{<nil>}`, ifacePath.String())
+}
+
+func TestValueDepthFirstWalkerNilAndInterface(t *testing.T) {
+	var (
+		methodNames []string
+		d           = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			methodNames = append(methodNames, m)
+		})
+		w   = NewValueDepthFirstWalker(d)
+		ptr *int
+		err error
+		st  = struct {
+			Ptr *int
+			Err error
+			Any interface{}
+		}{
+			Any: 1,
+		}
+	)
+
+	clear := func() {
+		methodNames = []string{}
+	}
+
+	// Nil
+	clear()
+	w.Walk(nil)
+	assert.Equal(t, []string{"VisitNil"}, methodNames)
+
+	// Nil ptr
+	clear()
+	w.Walk(ptr)
+	assert.Equal(t, []string{"VisitNil"}, methodNames)
+
+	// Nil interface
+	clear()
+	w.Walk(reflect.ValueOf(&err).Elem())
+	assert.Equal(t, []string{"VisitNil"}, methodNames)
+
+	// Struct with nil ptr, nil interface, and non-nil interface
+	clear()
+	w.Walk(st)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreStruct",
+			"VisitPreStructFieldValue",
+			"VisitNil",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitNil",
+			"VisitPostStructFieldValue",
+			"VisitPreStructFieldValue",
+			"VisitPreInterface",
+			"VisitInt",
+			"VisitPostInterface",
+			"VisitPostStructFieldValue",
+			"VisitPostStruct",
+		},
+		methodNames,
+	)
+
+	// Slice of interface that contains itself
+	s := []interface{}{nil}
+	s[0] = s
+	clear()
+	w.WithBackReferenceMode(BackReferenceNotify)
+	w.Walk(s)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice",
+			"VisitPreSliceIndex",
+			"VisitPreInterface",
+			"VisitBackReference",
+			"VisitPostInterface",
+			"VisitPostSliceIndex",
+			"VisitPostSlice",
+		},
+		methodNames,
+	)
+}
+
+// walkerTestController records visits, skips slices longer than 2 elements, and stops at the first map
+type walkerTestController struct {
+	visits []string
+}
+
+func (c *walkerTestController) VisitInt(i int) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitInt(%d)", i))
+}
+
+func (c *walkerTestController) VisitPreSlice(length int, _ reflect.Value) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitPreSlice(%d)", length))
+}
+
+func (c *walkerTestController) VisitPreSliceAction(length int, _ reflect.Value) WalkAction {
+	if length > 2 {
+		return SkipChildren
+	}
+
+	return Continue
+}
+
+func (c *walkerTestController) VisitPostSlice(length int, _ reflect.Value) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitPostSlice(%d)", length))
+}
+
+func (c *walkerTestController) VisitPreMapAction(int, reflect.Value) WalkAction {
+	c.visits = append(c.visits, "VisitPreMapAction")
+	return Stop
+}
+
+func (c *walkerTestController) VisitPostMap(int, reflect.Value) {
+	c.visits = append(c.visits, "VisitPostMap")
+}
+
+func TestValueDepthFirstWalkerWalkActions(t *testing.T) {
+	var (
+		c = &walkerTestController{}
+		w = NewValueDepthFirstWalker(NewValueVisitorAdapter(c))
+	)
+
+	// Skip children of long slices, but still postvisit them
+	w.Walk([][]int{{1, 2}, {3, 4, 5}, {6}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+		},
+		c.visits,
+	)
+
+	c.visits = nil
+	w.Walk([][]int{{1, 2}, {3, 4, 5}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitInt(1)",
+			"VisitInt(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitPostSlice(2)",
+		},
+		c.visits,
+	)
+
+	// Stop at first map, without any further visits
+	c.visits = nil
+	w.Walk([]interface{}{1, map[int]int{2: 3}, 4})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+		},
+		c.visits,
+	)
+
+	c.visits = nil
+	w.Walk([]interface{}{1, map[int]int{2: 3}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitInt(1)",
+			"VisitPreMapAction",
+		},
+		c.visits,
+	)
+}
+
+type walkerTestItem struct {
+	Name  string
+	Price *int
+}
+
+type walkerTestOrder struct {
+	Items map[string]walkerTestItem
+}
+
+// walkerTestValidator records the path of every empty string and nil pointer
+type walkerTestValidator struct {
+	path    *ValuePath
+	invalid []string
+}
+
+func (v *walkerTestValidator) Init() {
+	v.invalid = nil
+}
+
+func (v *walkerTestValidator) InitPath(path *ValuePath) {
+	v.path = path
+}
+
+func (v *walkerTestValidator) VisitString(s string) {
+	if s == "" {
+		v.invalid = append(v.invalid, v.path.String())
+	}
+}
+
+func (v *walkerTestValidator) VisitNil(reflect.Value) {
+	v.invalid = append(v.invalid, v.path.String())
+}
+
+func TestValueDepthFirstWalkerPath(t *testing.T) {
+	var (
+		v     = &walkerTestValidator{}
+		w     = NewValueDepthFirstWalker(NewValueVisitorAdapter(v))
+		price = 5
+	)
+
+	w.Walk(struct{ Orders []*walkerTestOrder }{
+		Orders: []*walkerTestOrder{
+			{Items: map[string]walkerTestItem{"sku": {Name: "shoe", Price: &price}}},
+			{Items: map[string]walkerTestItem{"sku": {Name: "", Price: &price}}},
+			{Items: map[string]walkerTestItem{"sku": {Name: "hat"}}},
+		},
+	})
+	assert.Equal(
+		t,
+		[]string{
+			`.Orders[1].Items["sku"].Name`,
+			`.Orders[2].Items["sku"].Price`,
+		},
+		v.invalid,
+	)
+
+	w.Walk(map[string]int{"": 1})
+	assert.Equal(t, []string{`{""}`}, v.invalid)
+}
+
+var errWalkerTestEmpty = fmt.Errorf("empty string")
+
+// walkerTestErrorVisitor fails on empty strings
+type walkerTestErrorVisitor struct {
+	strs []string
+}
+
+func (v *walkerTestErrorVisitor) Init() error {
+	v.strs = nil
+	return nil
+}
+
+func (v *walkerTestErrorVisitor) VisitString(s string) error {
+	if s == "" {
+		return errWalkerTestEmpty
+	}
+
+	v.strs = append(v.strs, s)
+	return nil
+}
+
+// walkerTestPanicVisitor panics on negative ints
+type walkerTestPanicVisitor struct{}
+
+func (walkerTestPanicVisitor) VisitInt(i int) {
+	if i < 0 {
+		panic("negative")
+	}
+}
+
+func TestValueDepthFirstWalkerWalkE(t *testing.T) {
+	var (
+		v = &walkerTestErrorVisitor{}
+		w = NewValueDepthFirstWalker(NewValueVisitorAdapter(v))
+	)
+
+	// Success
+	assert.Nil(t, w.WalkE([]string{"a", "b"}))
+	assert.Equal(t, []string{"a", "b"}, v.strs)
+
+	// First error aborts the walk
+	err := w.WalkE(map[string][]string{"k": {"a", "", "c"}})
+	assert.Equal(t, []string{"k", "a"}, v.strs)
+	assert.Equal(t, `goreflect: walk failed at "[\"k\"][1]": empty string`, err.Error())
+	assert.True(t, errors.Is(err, errWalkerTestEmpty))
+
+	var walkErr WalkError
+	assert.True(t, errors.As(err, &walkErr))
+	assert.Equal(t, `["k"][1]`, walkErr.Path.String())
+
+	// Walk panics with the error
+	func() {
+		defer func() {
+			assert.Equal(t, errWalkerTestEmpty, recover())
+		}()
+
+		w.Walk("")
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Legacy visitors that panic
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(walkerTestPanicVisitor{}))
+	err = w.WalkE(struct{ Foo, Bar int }{1, -1})
+	assert.Equal(t, `goreflect: walk failed at ".Bar": negative`, err.Error())
+
+	// Panics of the walker itself
+	first := &walkerTestNode{Name: "first"}
+	first.Next = first
+	err = w.WalkE(first)
+	assert.Equal(
+		t,
+		`goreflect: walk failed at ".Next": goreflect.ValueDepthFirstWalker.dispatch: value of type *goreflect.walkerTestNode at ".Next" refers back to ""`,
+		err.Error(),
+	)
+}
+
+func TestValueDepthFirstWalkerSortedMapKeys(t *testing.T) {
+	var (
+		p = NewValuePrinter().WithQuotedStrings()
+		w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	)
+
+	w.WithSortedMapKeys()
+
+	// Natural order
+	w.Walk(map[int]int{5: 0, -3: 1, 10: 2, 0: 3, 7: 4, 1: 5})
+	assert.Equal(t, "map[int]int{-3: 1, 0: 3, 1: 5, 5: 0, 7: 4, 10: 2}", p.Result())
+
+	w.Walk(map[uint8]int{5: 0, 3: 1, 10: 2})
+	assert.Equal(t, "map[uint8]int{3: 1, 5: 0, 10: 2}", p.Result())
+
+	w.Walk(map[float64]int{2.5: 0, -1.5: 1, 0: 2})
+	assert.Equal(t, "map[float64]int{-1.5: 1, 0: 2, 2.5: 0}", p.Result())
+
+	// NaN sorts before all other floats, and its value is walked, even though NaN cannot index the map
+	w.Walk(map[float64]int{2.5: 0, math.NaN(): 1, math.Inf(-1): 2})
+	assert.Equal(t, "map[float64]int{NaN: 1, -Inf: 2, 2.5: 0}", p.Result())
+
+	w.Walk(map[string]int{"b": 0, "c": 1, "a": 2})
+	assert.Equal(t, `map[string]int{"a": 2, "b": 0, "c": 1}`, p.Result())
+
+	w.Walk(map[bool]int{true: 0, false: 1})
+	assert.Equal(t, "map[bool]int{false: 1, true: 0}", p.Result())
+
+	// Interface keys sort by contained values, falling back to printed form for differing kinds
+	w.Walk(map[interface{}]int{"b": 0, 2: 1, "a": 2, 1: 3})
+	assert.Equal(t, `map[interface {}]int{"a": 2, "b": 0, 1: 3, 2: 1}`, p.Result())
+
+	// Printed form for other kinds
+	type key struct {
+		A int
+		B string
+	}
+	w.Walk(map[key]int{{2, "x"}: 0, {1, "y"}: 1, {1, "x"}: 2})
+	assert.Equal(
+		t,
+		`map[goreflect.key]int{goreflect.key{A: 1, B: "x"}: 2, goreflect.key{A: 1, B: "y"}: 1, goreflect.key{A: 2, B: "x"}: 0}`,
+		p.Result(),
+	)
+
+	// Pointer keys sort by what they point to
+	one, two := 1, 2
+	w.Walk(map[*int]int{&two: 0, &one: 1})
+	assert.Equal(t, "map[*int]int{&1: 1, &2: 0}", p.Result())
+
+	// Custom order
+	w.WithMapKeyComparator(func(a, b reflect.Value) bool {
+		return a.Int() > b.Int()
+	})
+	w.Walk(map[int]int{5: 0, -3: 1, 10: 2})
+	assert.Equal(t, "map[int]int{10: 2, 5: 0, -3: 1}", p.Result())
+
+	// Indexes follow the sorted order
+	var keys []string
+	w.WithSortedMapKeys()
+	w.WithVisitor(NewValueVisitorProxy(func(m string, a []reflect.Value) {
+		if m == "VisitPreMapKey" {
+			keys = append(keys, fmt.Sprintf("%d:%v", a[1].Interface(), a[2].Interface()))
+		}
+	}))
+	w.Walk(map[string]int{"b": 0, "c": 1, "a": 2})
+	assert.Equal(t, []string{"0:a", "1:b", "2:c"}, keys)
+
+	// Unsorted again
+	w.WithoutSortedMapKeys()
+	keys = nil
+	w.Walk(map[string]int{"b": 0, "c": 1, "a": 2})
+	assert.Equal(t, 3, len(keys))
+}
+
+type walkerTestTagged struct {
+	ID       int    `reflect:"id"`
+	Password string `reflect:"-"`
+	Name     string `reflect:",omitempty"`
+	internal int
+}
+
+func TestValueDepthFirstWalkerStructFields(t *testing.T) {
+	var (
+		p   = NewValuePrinter()
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		val = walkerTestTagged{ID: 1, Password: "secret", Name: "joe", internal: 2}
+	)
+
+	// All fields by default
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{ID: 1, Password: secret, Name: joe, internal: 2}", p.Result())
+
+	// The deprecated struct fields options have no effect
+	w.WithoutStructFields()
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{ID: 1, Password: secret, Name: joe, internal: 2}", p.Result())
+	w.WithStructFields()
+
+	// Exported fields only
+	w.WithExportedFieldsOnly()
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{ID: 1, Password: secret, Name: joe}", p.Result())
+
+	// Struct tag skips and renames fields
+	w.WithStructTag("reflect")
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{id: 1, Name: joe}", p.Result())
+
+	// Filter is passed the field before renaming
+	w.WithStructFieldFilter(func(f reflect.StructField) bool {
+		return f.Name != "ID"
+	})
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{Name: joe}", p.Result())
+
+	// Renamed fields are renamed in the path
+	v := &walkerTestValidator{}
+	w.WithVisitor(NewValueVisitorAdapter(v))
+	w.WithoutStructFieldFilter()
+	w.Walk(struct {
+		Val walkerTestTagged `reflect:"val"`
+	}{})
+	assert.Equal(t, []string{".val.Name"}, v.invalid)
+
+	// Everything again
+	w.WithVisitor(NewValueVisitorAdapter(p))
+	w.WithoutExportedFieldsOnly()
+	w.WithoutStructTag()
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestTagged{ID: 1, Password: secret, Name: joe, internal: 2}", p.Result())
+}
+
+type walkerTestBase struct {
+	ID   int
+	Name string
+}
+
+type walkerTestAudit struct {
+	Name    string
+	Updated string
+}
+
+type walkerTestEmbedding struct {
+	walkerTestBase
+	*walkerTestAudit
+	Name string
+}
+
+type walkerTestAmbiguous struct {
+	walkerTestBase
+	walkerTestAudit
+}
+
+type walkerTestRecursive struct {
+	*walkerTestRecursive
+	Depth int
+}
+
+func TestValueDepthFirstWalkerPromotedFields(t *testing.T) {
+	var (
+		p   = NewValuePrinter()
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		val = walkerTestEmbedding{
+			walkerTestBase:  walkerTestBase{ID: 1, Name: "base"},
+			walkerTestAudit: &walkerTestAudit{Name: "audit", Updated: "today"},
+			Name:            "outer",
+		}
+	)
+
+	// Embedded structs are nested by default
+	w.Walk(walkerTestAmbiguous{})
+	assert.Equal(
+		t,
+		"goreflect.walkerTestAmbiguous{walkerTestBase: goreflect.walkerTestBase{ID: 0, Name: }, walkerTestAudit: goreflect.walkerTestAudit{Name: , Updated: }}",
+		p.Result(),
+	)
+
+	// Promoted fields are inline, and shadowed by shallower fields of the same name
+	w.WithPromotedFields()
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestEmbedding{ID: 1, Updated: today, Name: outer}", p.Result())
+
+	// Fields of the same name at the same depth are ambiguous
+	w.Walk(walkerTestAmbiguous{walkerTestBase{ID: 2}, walkerTestAudit{Updated: "now"}})
+	assert.Equal(t, "goreflect.walkerTestAmbiguous{ID: 2, Updated: now}", p.Result())
+
+	// Fields promoted through a nil pointer are omitted
+	val.walkerTestAudit = nil
+	w.Walk(val)
+	assert.Equal(t, "goreflect.walkerTestEmbedding{ID: 1, Name: outer}", p.Result())
+
+	// A struct that embeds a pointer to itself is only expanded once
+	w.Walk(walkerTestRecursive{&walkerTestRecursive{Depth: 1}, 0})
+	assert.Equal(t, "goreflect.walkerTestRecursive{Depth: 0}", p.Result())
+
+	// Promoted fields have the full index, and are selected in the path
+	var indexes [][]int
+	v := &walkerTestValidator{}
+	w.WithVisitor(NewValueVisitorAdapter(v))
+	w.WithStructFieldFilter(func(f reflect.StructField) bool {
+		indexes = append(indexes, f.Index)
+		return true
+	})
+	w.Walk(walkerTestAmbiguous{walkerTestBase{ID: 2}, walkerTestAudit{Updated: "now"}})
+	assert.Equal(t, [][]int{{0}, {0, 0}, {0, 1}, {1}, {1, 0}, {1, 1}}, indexes)
+
+	w.WithoutStructFieldFilter()
+	w.Walk(walkerTestEmbedding{walkerTestAudit: &walkerTestAudit{}})
+	assert.Equal(t, []string{".Updated", ".Name"}, v.invalid)
+}
+
+func TestValueDepthFirstWalkerLimits(t *testing.T) {
+	var (
+		truncated []string
+		d         = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			if m == "VisitTruncated" {
+				truncated = append(truncated, fmt.Sprintf("%s:%d", a[0].Interface(), a[1].Interface()))
+			}
+		})
+		w = NewValueDepthFirstWalker(d)
+	)
+
+	// Elements per collection
+	w.WithMaxElements(2)
+	w.Walk([][]int{{1, 2, 3}, {4}, {5, 6, 7, 8}})
+	assert.Equal(t, []string{"slice:1", "slice:1"}, truncated)
+
+	// Depth, where the top level value has depth 1
+	truncated = nil
+	w.WithoutMaxElements()
+	w.WithMaxDepth(2)
+	w.Walk([][]int{{1, 2, 3}, {4}})
+	assert.Equal(t, []string{"slice:3", "slice:1"}, truncated)
+
+	truncated = nil
+	i := 1
+	w.Walk(struct{ P **int }{P: func() **int { p := &i; return &p }()})
+	assert.Equal(t, []string{"ptr:1"}, truncated)
+
+	// Total nodes, which truncates every value being walked
+	truncated = nil
+	w.WithoutMaxDepth()
+	w.WithMaxNodes(4)
+	w.Walk([][]int{{1, 2, 3}, {4}})
+	assert.Equal(t, []string{"slice:1", "slice:1"}, truncated)
+
+	truncated = nil
+	w.WithoutMaxNodes()
+	w.Walk([][]int{{1, 2, 3}, {4}})
+	assert.Equal(t, []string(nil), truncated)
+}
+
+// walkerTestContextVisitor counts ints, cancelling the walk at a given count, and fails on negative ints if the context is cancelled
+type walkerTestContextVisitor struct {
+	ctx      context.Context
+	cancel   func()
+	cancelAt int
+	count    int
+}
+
+func (v *walkerTestContextVisitor) InitContext(ctx context.Context) {
+	v.ctx = ctx
+}
+
+func (v *walkerTestContextVisitor) VisitInt(i int) error {
+	if i < 0 {
+		return v.ctx.Err()
+	}
+
+	v.count++
+	if v.count == v.cancelAt {
+		v.cancel()
+	}
+
+	return nil
+}
+
+func TestValueDepthFirstWalkerWalkContext(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		v           = &walkerTestContextVisitor{cancel: cancel, cancelAt: 10}
+		w           = NewValueDepthFirstWalker(NewValueVisitorAdapter(v))
+	)
+	defer cancel()
+
+	// Uncancelled
+	assert.Nil(t, w.WalkContext(context.Background(), []int{1, 2, 3}))
+	assert.Equal(t, 3, v.count)
+	assert.Equal(t, context.Background(), v.ctx)
+
+	// Cancelled part way, the context is checked periodically
+	v.count = 0
+	assert.Equal(t, context.Canceled, w.WalkContext(ctx, make([]int, 5000)))
+	assert.Equal(t, contextCheckInterval-1, v.count)
+	assert.Equal(t, ctx, v.ctx)
+
+	// Already cancelled
+	v.count = 0
+	assert.Equal(t, context.Canceled, w.WalkContext(ctx, []int{1, 2, 3}))
+	assert.Equal(t, 0, v.count)
+
+	// Visitors can cooperate with the context, before the walker checks it again
+	ctx, cancel = context.WithCancel(context.Background())
+	v.count, v.cancel, v.cancelAt = 0, cancel, 1
+	err := w.WalkContext(ctx, []int{1, -1})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, `goreflect: walk failed at "[1]": context canceled`, err.Error())
+
+	// Walk without a context uses the background context
+	w.Walk(1)
+	assert.Equal(t, context.Background(), v.ctx)
+}
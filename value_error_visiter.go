@@ -0,0 +1,274 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The interfaces in this file are error returning variants of the interfaces in ValueVisitor.
+// Each method has the same name and parameters as the ValueVisitor method, but returns an error.
+// A visitor may implement either variant of each method, and ValueVisitorAdapter adapts it into a ValueVisitor.
+
+// InitErrorVisitor initializes the visiting process, and may fail
+type InitErrorVisitor interface {
+	Init() error
+}
+
+// BoolErrorVisitor visits bool values, and may fail
+type BoolErrorVisitor interface {
+	VisitBool(bool) error
+}
+
+// IntErrorVisitor visits int values, and may fail
+type IntErrorVisitor interface {
+	VisitInt(int) error
+}
+
+// Int8ErrorVisitor visits int8 values, and may fail
+type Int8ErrorVisitor interface {
+	VisitInt8(int8) error
+}
+
+// Int16ErrorVisitor visits int16 values, and may fail
+type Int16ErrorVisitor interface {
+	VisitInt16(int16) error
+}
+
+// Int32ErrorVisitor visits int32 values, and may fail
+type Int32ErrorVisitor interface {
+	VisitInt32(int32) error
+}
+
+// Int64ErrorVisitor visits int64 values, and may fail
+type Int64ErrorVisitor interface {
+	VisitInt64(int64) error
+}
+
+// UintErrorVisitor visits uint values, and may fail
+type UintErrorVisitor interface {
+	VisitUint(uint) error
+}
+
+// Uint8ErrorVisitor visits uint8 values, and may fail
+type Uint8ErrorVisitor interface {
+	VisitUint8(uint8) error
+}
+
+// Uint16ErrorVisitor visits uint16 values, and may fail
+type Uint16ErrorVisitor interface {
+	VisitUint16(uint16) error
+}
+
+// Uint32ErrorVisitor visits uint32 values, and may fail
+type Uint32ErrorVisitor interface {
+	VisitUint32(uint32) error
+}
+
+// Uint64ErrorVisitor visits uint64 values, and may fail
+type Uint64ErrorVisitor interface {
+	VisitUint64(uint64) error
+}
+
+// Float32ErrorVisitor visits float32 values, and may fail
+type Float32ErrorVisitor interface {
+	VisitFloat32(float32) error
+}
+
+// Float64ErrorVisitor visits float64 values, and may fail
+type Float64ErrorVisitor interface {
+	VisitFloat64(float64) error
+}
+
+// Complex64ErrorVisitor visits complex64 values, and may fail
+type Complex64ErrorVisitor interface {
+	VisitComplex64(complex64) error
+}
+
+// Complex128ErrorVisitor visits complex128 values, and may fail
+type Complex128ErrorVisitor interface {
+	VisitComplex128(complex128) error
+}
+
+// StringErrorVisitor visits string values, and may fail
+type StringErrorVisitor interface {
+	VisitString(string) error
+}
+
+// ChanErrorVisitor visits chan values, and may fail
+type ChanErrorVisitor interface {
+	VisitChan(reflect.Value) error
+}
+
+// FuncErrorVisitor visits func values, and may fail
+type FuncErrorVisitor interface {
+	VisitFunc(reflect.Value) error
+}
+
+// NilErrorVisitor visits nil ptr and interface values, and the invalid value that results from walking a nil, and may fail
+type NilErrorVisitor interface {
+	VisitNil(reflect.Value) error
+}
+
+// PrePtrErrorVisitor previsits ptr values, and may fail
+type PrePtrErrorVisitor interface {
+	VisitPrePtr(reflect.Value) error
+}
+
+// PostPtrErrorVisitor postvisits ptr values, and may fail
+type PostPtrErrorVisitor interface {
+	VisitPostPtr(reflect.Value) error
+}
+
+// PreInterfaceErrorVisitor previsits non-nil interface values, and may fail
+type PreInterfaceErrorVisitor interface {
+	VisitPreInterface(reflect.Value) error
+}
+
+// PostInterfaceErrorVisitor postvisits non-nil interface values, and may fail
+type PostInterfaceErrorVisitor interface {
+	VisitPostInterface(reflect.Value) error
+}
+
+// PreArrayErrorVisitor previsits array values, and may fail
+type PreArrayErrorVisitor interface {
+	VisitPreArray(length int, v reflect.Value) error
+}
+
+// PreArrayIndexErrorVisitor previsits array value indexes, and may fail
+type PreArrayIndexErrorVisitor interface {
+	VisitPreArrayIndex(length int, index int, v reflect.Value) error
+}
+
+// PostArrayIndexErrorVisitor postvisits array value indexes, and may fail
+type PostArrayIndexErrorVisitor interface {
+	VisitPostArrayIndex(length int, index int, v reflect.Value) error
+}
+
+// PostArrayErrorVisitor postvisits array values, and may fail
+type PostArrayErrorVisitor interface {
+	VisitPostArray(length int, v reflect.Value) error
+}
+
+// PreSliceErrorVisitor previsits slice values, and may fail
+type PreSliceErrorVisitor interface {
+	VisitPreSlice(length int, v reflect.Value) error
+}
+
+// PreSliceIndexErrorVisitor previsits slice value indexes, and may fail
+type PreSliceIndexErrorVisitor interface {
+	VisitPreSliceIndex(length int, index int, v reflect.Value) error
+}
+
+// PostSliceIndexErrorVisitor prostvisits slice value indexes, and may fail
+type PostSliceIndexErrorVisitor interface {
+	VisitPostSliceIndex(length int, index int, v reflect.Value) error
+}
+
+// PostSliceErrorVisitor postvisits slice values, and may fail
+type PostSliceErrorVisitor interface {
+	VisitPostSlice(length int, v reflect.Value) error
+}
+
+// PreMapErrorVisitor previsits map values, and may fail
+type PreMapErrorVisitor interface {
+	VisitPreMap(length int, m reflect.Value) error
+}
+
+// PreMapKeyValueErrorVisitor previsits map key/value pairs, and may fail
+type PreMapKeyValueErrorVisitor interface {
+	VisitPreMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) error
+}
+
+// PreMapKeyErrorVisitor previsits map keys, and may fail
+type PreMapKeyErrorVisitor interface {
+	VisitPreMapKey(length int, index int, k reflect.Value) error
+}
+
+// PostMapKeyErrorVisitor postvisits map keys, and may fail
+type PostMapKeyErrorVisitor interface {
+	VisitPostMapKey(length int, index int, k reflect.Value) error
+}
+
+// PreMapValueErrorVisitor previsits map key values, and may fail
+type PreMapValueErrorVisitor interface {
+	VisitPreMapValue(length int, index int, v reflect.Value) error
+}
+
+// PostMapValueErrorVisitor postvisits map key values, and may fail
+type PostMapValueErrorVisitor interface {
+	VisitPostMapValue(length int, index int, v reflect.Value) error
+}
+
+// PostMapKeyValueErrorVisitor postvisits map key/value pairs, and may fail
+type PostMapKeyValueErrorVisitor interface {
+	VisitPostMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) error
+}
+
+// PostMapErrorVisitor postvisits map values, and may fail
+type PostMapErrorVisitor interface {
+	VisitPostMap(length int, m reflect.Value) error
+}
+
+// PreStructErrorVisitor previsits struct values, and may fail
+type PreStructErrorVisitor interface {
+	VisitPreStruct(length int, v reflect.Value) error
+}
+
+// PreStructFieldValueErrorVisitor previsits struct field value pairs, and may fail
+type PreStructFieldValueErrorVisitor interface {
+	VisitPreStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) error
+}
+
+// PostStructFieldValueErrorVisitor postvisits struct field value pairs, and may fail
+type PostStructFieldValueErrorVisitor interface {
+	VisitPostStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) error
+}
+
+// PostStructErrorVisitor postvisits struct values, and may fail
+type PostStructErrorVisitor interface {
+	VisitPostStruct(length int, v reflect.Value) error
+}
+
+// BackReferenceErrorVisitor visits values that refer back to a value that has already been walked, and may fail
+type BackReferenceErrorVisitor interface {
+	VisitBackReference(v reflect.Value, path ValuePath) error
+}
+
+// TruncatedErrorVisitor visits the components of a value that were not walked because a walker limit was reached, and may fail
+type TruncatedErrorVisitor interface {
+	VisitTruncated(kind reflect.Kind, remaining int) error
+}
+
+// WalkError is the error returned by a walk that failed, and describes where the walk failed
+type WalkError struct {
+	Path ValuePath
+	Err  error
+}
+
+// Error returns the message of the underlying error, with the path the walk failed at
+func (e WalkError) Error() string {
+	return fmt.Sprintf("goreflect: walk failed at %q: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e WalkError) Unwrap() error {
+	return e.Err
+}
+
+// panicOnError panics with the given error if it is not nil
+func panicOnError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// recoveredWalkError converts a value recovered from a panic into a WalkError at the given path.
+// If the value is not an error, the underlying error describes the value.
+func recoveredWalkError(path ValuePath, recovered interface{}) WalkError {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+
+	return WalkError{Path: path.Copy(), Err: err}
+}
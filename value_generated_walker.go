@@ -0,0 +1,147 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GeneratedWalker is the state of a walk by a walker generated by goreflect-gen (see cmd/goreflect-gen).
+// Generated walkers walk values of concrete types without reflection, and use a GeneratedWalker to call the visitor
+// and track the walk in exactly the same way as a ValueDepthFirstWalker, so that both call the same visitor methods
+// in the same order. It is not intended to be used by hand.
+//
+// A GeneratedWalker has the visitor and options of a ValueDepthFirstWalker, except that struct field options and
+// limits are not supported, as generated walkers always walk every struct field and component.
+type GeneratedWalker struct {
+	walker ValueDepthFirstWalker
+	st     *valueWalkState
+}
+
+// NewGeneratedWalker constructs a GeneratedWalker for a single walk with the visitor and options of the given walker,
+// and initializes the visitor.
+// Panics if the walker has struct field options or limits.
+func NewGeneratedWalker(w ValueDepthFirstWalker) *GeneratedWalker {
+	if w.exportedFields || (w.structTag != "") || (w.structFieldFilter != nil) || w.promotedFields ||
+		(w.maxDepth > 0) || (w.maxElements > 0) || (w.maxNodes > 0) {
+		panic(fmt.Errorf("goreflect.NewGeneratedWalker: generated walkers do not support struct field options or limits"))
+	}
+
+	g := &GeneratedWalker{walker: w, st: newValueWalkState(w.visitor)}
+	g.st.initVisitor()
+
+	return g
+}
+
+// GeneratedStructFields returns the fields of a struct type, in the order a walker visits them.
+// The type passed can be a reflect.Type, or a value or reflect.Value of the type.
+func GeneratedStructFields(typ interface{}) []reflect.StructField {
+	t := GetReflectTypeOf(typ)
+	fields := make([]reflect.StructField, t.NumField())
+	for i := range fields {
+		fields[i] = t.Field(i)
+	}
+
+	return fields
+}
+
+// Visitor returns the visitor
+func (g *GeneratedWalker) Visitor() ValueVisitor {
+	return g.walker.visitor
+}
+
+// Stopped returns true if the visitor has stopped the walk
+func (g *GeneratedWalker) Stopped() bool {
+	return g.st.stopped
+}
+
+// MapKeyLess returns the comparator for sorting map keys, or nil if map keys are not sorted
+func (g *GeneratedWalker) MapKeyLess() func(a, b reflect.Value) bool {
+	return g.walker.mapKeyLess
+}
+
+// Push adds an element to the path of the value being walked
+func (g *GeneratedWalker) Push(e ValuePathElement) {
+	g.st.path.push(e)
+}
+
+// Pop removes the last element of the path of the value being walked
+func (g *GeneratedWalker) Pop() {
+	g.st.path.pop()
+}
+
+// Enter records that a non-nil pointer, slice, or map is about to be walked, and returns true if it should be walked.
+// It returns false if it is a back reference, after handling it according to the back reference mode.
+// If true is returned, Leave must be called after the value is walked.
+func (g *GeneratedWalker) Enter(v reflect.Value) bool {
+	return g.walker.enter(v, g.st)
+}
+
+// Leave records that a pointer, slice, or map that Enter returned true for is no longer being walked
+func (g *GeneratedWalker) Leave(v reflect.Value) {
+	g.walker.leave(v, g.st)
+}
+
+// PrePtr previsits a pointer, and returns true if the value it points to should be walked
+func (g *GeneratedWalker) PrePtr(v reflect.Value) bool {
+	return g.st.prePtr(v)
+}
+
+// PreArray previsits an array, and returns true if its elements should be walked
+func (g *GeneratedWalker) PreArray(length int, v reflect.Value) bool {
+	return g.st.preArray(length, v)
+}
+
+// PreSlice previsits a slice, and returns true if its elements should be walked
+func (g *GeneratedWalker) PreSlice(length int, v reflect.Value) bool {
+	return g.st.preSlice(length, v)
+}
+
+// PreMap previsits a map, and returns true if its keys and values should be walked
+func (g *GeneratedWalker) PreMap(length int, v reflect.Value) bool {
+	return g.st.preMap(length, v)
+}
+
+// PreStruct previsits a struct, and returns true if its fields should be walked
+func (g *GeneratedWalker) PreStruct(length int, v reflect.Value) bool {
+	return g.st.preStruct(length, v)
+}
+
+// Dispatch walks a value with reflection, for values that cannot be walked by generated code,
+// such as the value contained in an interface, or a struct with fields that are not accessible
+func (g *GeneratedWalker) Dispatch(v reflect.Value) {
+	g.walker.dispatch(v, g.st)
+}
+
+// CompareWalks returns an error describing the first difference between the visits made by w.Walk(val), and
+// the visits made by the given walk function, which is intended to be a walker generated by goreflect-gen, or nil if
+// they are the same. Both walks are recorded by a ValueRecorder set as the visitor of w.
+// As map keys must be in the same order for both walks, they are sorted if w does not already sort them.
+func CompareWalks(w ValueDepthFirstWalker, val interface{}, walk func(w ValueDepthFirstWalker)) error {
+	if w.mapKeyLess == nil {
+		w.WithSortedMapKeys()
+	}
+
+	var (
+		walkerRecorder    = NewValueRecorder()
+		generatedRecorder = NewValueRecorder()
+	)
+
+	w.WithVisitor(walkerRecorder)
+	w.Walk(val)
+
+	w.WithVisitor(generatedRecorder)
+	walk(w)
+
+	walkerEvents, generatedEvents := walkerRecorder.Events(), generatedRecorder.Events()
+	for i := 0; (i < len(walkerEvents)) && (i < len(generatedEvents)); i++ {
+		if !reflect.DeepEqual(walkerEvents[i], generatedEvents[i]) {
+			return fmt.Errorf("goreflect.CompareWalks: event %d differs: the walker visited %+v, the generated walker visited %+v", i, walkerEvents[i], generatedEvents[i])
+		}
+	}
+
+	if len(walkerEvents) != len(generatedEvents) {
+		return fmt.Errorf("goreflect.CompareWalks: the walker made %d visits, the generated walker made %d", len(walkerEvents), len(generatedEvents))
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// ValueParallelWalker walks the elements of a large collection in parallel, for visitors that aggregate results,
+// such as sums or counts, that do not depend on the order or structure of the values visited.
+//
+// The walk partitions the elements of the top level array, slice, or map into contiguous chunks, one per worker.
+// Each worker walks the elements of its chunk depth first, with its own visitor from a factory. When all workers have
+// finished, the visitors are merged left to right in chunk order by a reducer, and the result is returned.
+// With a partition depth greater than 1, the elements of the elements are partitioned instead, and so on.
+// Pointers and interfaces are walked through without counting as a level, and any value that is not an array,
+// slice, or map before the partition depth is reached is partitioned as a single element.
+// A map entry is partitioned as two elements, its key and its value.
+//
+// The visitors are only given the partitioned elements and the values they contain. Values above the partition depth are
+// not visited, there is no single VisitPreSlice/VisitPostSlice for the top level slice, and so on.
+// Each visitor is initialized once, and is given the path of each element from the top level value if it is PathAware.
+//
+// The options are the same as for a ValueDepthFirstWalker, and apply to each chunk separately:
+// back and shared references are only detected within a chunk, and node limits apply to each chunk.
+// A visitor that stops the walk only stops the walk of its own chunk.
+// The visitor set by WithVisitor is not used, as each worker needs its own visitor.
+type ValueParallelWalker struct {
+	valueWalkerOptions
+	factory        func() interface{}
+	reducer        func(a, b interface{}) interface{}
+	workers        int
+	partitionDepth int
+}
+
+// valueParallelElement is a partitioned element, with its path from the top level value
+type valueParallelElement struct {
+	value reflect.Value
+	path  ValuePath
+}
+
+// NewValueParallelWalker constructs a ValueParallelWalker with a visitor factory and a reducer.
+// The factory returns a new visitor for each worker, which can be any subset of ValueVisitor (see ValueVisitorAdapter).
+// The reducer merges two visitors returned by the factory, and returns the merged visitor.
+// By default, there is one worker for each CPU, and the elements of the top level value are partitioned.
+func NewValueParallelWalker(factory func() interface{}, reducer func(a, b interface{}) interface{}) ValueParallelWalker {
+	return ValueParallelWalker{
+		factory:        factory,
+		reducer:        reducer,
+		workers:        runtime.GOMAXPROCS(0),
+		partitionDepth: 1,
+	}
+}
+
+// WithWorkers sets the number of workers.
+// Panics if workers is less than 1.
+func (w *ValueParallelWalker) WithWorkers(workers int) {
+	if workers < 1 {
+		panic(fmt.Errorf("goreflect.ValueParallelWalker.WithWorkers: workers must be at least 1, not %d", workers))
+	}
+
+	w.workers = workers
+}
+
+// WithPartitionDepth sets the depth of the elements to partition, where 1 is the elements of the top level value
+func (w *ValueParallelWalker) WithPartitionDepth(depth int) {
+	w.partitionDepth = depth
+}
+
+// Walk walks the given value in parallel, and returns the visitors merged by the reducer.
+// The value passed can be a reflect.Value wrapper or a plain value.
+// If any worker panics, a WalkError for the first chunk that panicked is raised once all workers have finished.
+func (w ValueParallelWalker) Walk(val interface{}) interface{} {
+	result, err := w.walk(val)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// WalkE is the same as Walk, except that a panic is recovered and returned as a WalkError (see ValueDepthFirstWalker.WalkE).
+// If more than one chunk fails, the error of the first chunk is returned.
+func (w ValueParallelWalker) WalkE(val interface{}) (interface{}, error) {
+	return w.walk(val)
+}
+
+// walk partitions the value into chunks, walks each chunk in its own goroutine, and reduces the results.
+// If any chunk panics, the WalkError of the first such chunk is returned instead of a result.
+func (w ValueParallelWalker) walk(val interface{}) (interface{}, error) {
+	var (
+		elements = w.partition(GetReflectValueOf(val), ValuePath{}, w.partitionDepth, nil, nil)
+		chunks   = w.workers
+	)
+
+	if chunks > len(elements) {
+		chunks = len(elements)
+	}
+
+	if chunks == 0 {
+		visitor := w.factory()
+		NewValueVisitorAdapter(visitor).Init()
+		return visitor, nil
+	}
+
+	var (
+		visitors = make([]interface{}, chunks)
+		errs     = make([]error, chunks)
+		wg       sync.WaitGroup
+	)
+
+	for c := 0; c < chunks; c++ {
+		wg.Add(1)
+
+		go func(c int) {
+			defer wg.Done()
+
+			visitors[c] = w.factory()
+			errs[c] = w.walkChunk(visitors[c], elements[c*len(elements)/chunks:(c+1)*len(elements)/chunks])
+		}(c)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := visitors[0]
+	for _, visitor := range visitors[1:] {
+		result = w.reducer(result, visitor)
+	}
+
+	return result, nil
+}
+
+// walkChunk walks the elements of a chunk with the given visitor, and returns a WalkError if the walk panics
+func (w ValueParallelWalker) walkChunk(visitor interface{}, elements []valueParallelElement) (err error) {
+	var (
+		dfw = ValueDepthFirstWalker{w.valueWalkerOptions}
+		st  *valueWalkState
+	)
+
+	dfw.visitor = NewValueVisitorAdapter(visitor)
+	st = newValueWalkState(dfw.visitor)
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoveredWalkError(st.path, recovered)
+		}
+	}()
+
+	st.initVisitor()
+	for _, e := range elements {
+		if st.stopped {
+			break
+		}
+
+		st.path = e.path
+		dfw.dispatch(e.value, st)
+	}
+
+	return nil
+}
+
+// partition appends the elements of the given value at the given depth to the given elements, and returns them.
+// The derefs are the pointers walked through since the last array, slice, or map, so that a cycle of pointers
+// is partitioned as a single element, leaving the walk of the element to handle the back reference.
+func (w ValueParallelWalker) partition(v reflect.Value, path ValuePath, depth int, derefs []valueRef, elements []valueParallelElement) []valueParallelElement {
+	child := func(e ValuePathElement) ValuePath {
+		childPath := path.Copy()
+		childPath.push(e)
+		return childPath
+	}
+
+	if depth > 0 {
+		switch v.Kind() {
+		case reflect.Ptr:
+			if ref, trackable := refOf(v); trackable && !containsValueRef(derefs, ref) {
+				return w.partition(v.Elem(), child(ValuePathElement{Kind: PathDeref}), depth, append(derefs, ref), elements)
+			}
+
+		case reflect.Interface:
+			if !v.IsNil() {
+				return w.partition(v.Elem(), child(ValuePathElement{Kind: PathInterface}), depth, derefs, elements)
+			}
+
+		case reflect.Array, reflect.Slice:
+			for i, n := 0, v.Len(); i < n; i++ {
+				elements = w.partition(v.Index(i), child(ValuePathElement{Kind: PathIndex, Index: i}), depth-1, nil, elements)
+			}
+
+			return elements
+
+		case reflect.Map:
+			iter := w.mapIter(v)
+			for i := 0; ; i++ {
+				mk, mv, ok := iter()
+				if !ok {
+					break
+				}
+
+				elements = w.partition(mk, child(ValuePathElement{Kind: PathMapKey, Index: i, Key: mk}), depth-1, nil, elements)
+				elements = w.partition(mv, child(ValuePathElement{Kind: PathMapValue, Index: i, Key: mk}), depth-1, nil, elements)
+			}
+
+			return elements
+		}
+	}
+
+	return append(elements, valueParallelElement{value: v, path: path})
+}
+
+// containsValueRef returns true if the given refs contain the given ref
+func containsValueRef(refs []valueRef, ref valueRef) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package goreflect
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parallelTestSum sums ints, failing on negative ints, and records the path of each int
+type parallelTestSum struct {
+	path  *ValuePath
+	sum   int
+	paths []string
+}
+
+func (s *parallelTestSum) Init() {
+	s.sum, s.paths = 0, nil
+}
+
+func (s *parallelTestSum) InitPath(path *ValuePath) {
+	s.path = path
+}
+
+func (s *parallelTestSum) VisitInt(i int) error {
+	if i < 0 {
+		return fmt.Errorf("negative")
+	}
+
+	s.sum += i
+	s.paths = append(s.paths, s.path.String())
+	return nil
+}
+
+func TestValueParallelWalker(t *testing.T) {
+	var (
+		w = NewValueParallelWalker(
+			func() interface{} { return &parallelTestSum{} },
+			func(a, b interface{}) interface{} {
+				sa, sb := a.(*parallelTestSum), b.(*parallelTestSum)
+				sa.sum += sb.sum
+				sa.paths = append(sa.paths, sb.paths...)
+				return sa
+			},
+		)
+		ints = make([]int, 10000)
+	)
+
+	for i := range ints {
+		ints[i] = i
+	}
+
+	// Sum across workers
+	w.WithWorkers(4)
+	assert.Equal(t, 49995000, w.Walk(ints).(*parallelTestSum).sum)
+
+	// There must be at least one worker
+	for _, workers := range []int{0, -1} {
+		func() {
+			defer func() {
+				assert.Equal(t, fmt.Errorf("goreflect.ValueParallelWalker.WithWorkers: workers must be at least 1, not %d", workers), recover())
+			}()
+
+			w.WithWorkers(workers)
+			assert.Fail(t, "Must panic")
+		}()
+	}
+	assert.Equal(t, 49995000, w.Walk(ints).(*parallelTestSum).sum)
+
+	// More workers than elements, results are reduced in element order with paths from the top level value
+	w.WithWorkers(10)
+	assert.Equal(t, []string{"[0]", "[1]", "[2]"}, w.Walk(&[]int{1, 2, 3}).(*parallelTestSum).paths)
+
+	// Empty
+	assert.Equal(t, &parallelTestSum{}, w.Walk([]int{}))
+
+	// Partition depth
+	w.WithWorkers(2)
+	w.WithPartitionDepth(2)
+	assert.Equal(
+		t,
+		[]string{"[0][0]", "[0][1]", "[1][0]", "[2].Foo", "[3]"},
+		w.Walk([]interface{}{[]int{1, 2}, []int{3}, struct{ Foo int }{4}, 5}).(*parallelTestSum).paths,
+	)
+
+	// Map keys and values are separate elements
+	w.WithPartitionDepth(1)
+	w.WithSortedMapKeys()
+	assert.Equal(
+		t,
+		[]string{"{1}", "[1]", "{3}", "[3]"},
+		w.Walk(map[int]int{3: 4, 1: 2}).(*parallelTestSum).paths,
+	)
+
+	// Errors
+	ints[7] = -1
+	_, err := w.WalkE(ints)
+	assert.Equal(t, `goreflect: walk failed at "[7]": negative`, err.Error())
+
+	var walkErr WalkError
+	func() {
+		defer func() {
+			assert.True(t, errors.As(recover().(error), &walkErr))
+		}()
+
+		w.Walk(ints)
+		assert.Fail(t, "Must panic")
+	}()
+	assert.Equal(t, "[7]", walkErr.Path.String())
+
+	// A cycle of pointers is a single element, handled by the back reference mode
+	var p walkerTestPtr
+	p = &p
+	w.WithBackReferenceMode(BackReferenceSkip)
+	assert.Equal(t, 0, w.Walk(p).(*parallelTestSum).sum)
+}
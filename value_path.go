@@ -0,0 +1,115 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValuePathElementKind is an enum of the kinds of steps a walker can take from a value to one of its components
+type ValuePathElementKind uint
+
+// Value path element kinds
+const (
+	PathDeref     ValuePathElementKind = iota // dereference of a pointer
+	PathIndex                                 // index of an array or slice
+	PathMapKey                                // key of a map entry
+	PathMapValue                              // value of a map entry
+	PathField                                 // field of a struct
+	PathInterface                             // value contained in an interface
+)
+
+// ValuePathElement is a single step in a ValuePath.
+// Only the members relevant to the Kind are set:
+// - PathDeref and PathInterface: none
+// - PathIndex: Index
+// - PathMapKey and PathMapValue: Index (the iteration index of the map entry) and Key
+// - PathField: Index and Name
+type ValuePathElement struct {
+	Kind  ValuePathElementKind
+	Index int
+	Key   reflect.Value
+	Name  string
+}
+
+// String returns the element in Go selector/index syntax:
+// - PathDeref and PathInterface: the empty string, as Go implicitly dereferences pointers in selectors
+// - PathIndex: [index]
+// - PathMapKey: {key}
+// - PathMapValue: [key]
+// - PathField: .name
+// String keys are double quoted, other keys are printed with fmt.
+func (e ValuePathElement) String() string {
+	switch e.Kind {
+	case PathIndex:
+		return "[" + strconv.Itoa(e.Index) + "]"
+	case PathMapKey:
+		return "{" + mapKeyString(e.Key) + "}"
+	case PathMapValue:
+		return "[" + mapKeyString(e.Key) + "]"
+	case PathField:
+		return "." + e.Name
+	}
+
+	return ""
+}
+
+// mapKeyString returns a string representation of a map key.
+// A key of an interface type is represented by the value it contains, so that string keys are always quoted.
+func mapKeyString(key reflect.Value) string {
+	if (key.Kind() == reflect.Interface) && !key.IsNil() {
+		key = key.Elem()
+	}
+
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+
+	return fmt.Sprint(key)
+}
+
+// ValuePath describes the location of a value relative to the top level value given to a walker.
+// The zero value is the path of the top level value.
+type ValuePath struct {
+	elements []ValuePathElement
+}
+
+// String returns the path as a sequence of selectors and indexes, eg .Orders[3].Items["sku"].Price.
+// The path of the top level value is the empty string.
+func (p ValuePath) String() string {
+	var bldr strings.Builder
+
+	for _, e := range p.elements {
+		bldr.WriteString(e.String())
+	}
+
+	return bldr.String()
+}
+
+// Len returns the number of elements in the path
+func (p ValuePath) Len() int {
+	return len(p.elements)
+}
+
+// Elements returns a copy of the elements of the path
+func (p ValuePath) Elements() []ValuePathElement {
+	elementsCopy := make([]ValuePathElement, len(p.elements))
+	copy(elementsCopy, p.elements)
+	return elementsCopy
+}
+
+// Copy returns a copy of the path that is unaffected by further changes to this path
+func (p ValuePath) Copy() ValuePath {
+	return ValuePath{elements: p.Elements()}
+}
+
+// push adds an element to the end of the path
+func (p *ValuePath) push(e ValuePathElement) {
+	p.elements = append(p.elements, e)
+}
+
+// pop removes the last element of the path
+func (p *ValuePath) pop() {
+	p.elements = p.elements[:len(p.elements)-1]
+}
@@ -1,22 +1,92 @@
 package goreflect
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// StringEscapeMode is an enum of ways to escape quoted strings
+type StringEscapeMode uint
+
+// String escape modes
+const (
+	StringEscapeGo   StringEscapeMode = iota // escape as a Go string literal, the same as strconv.Quote
+	StringEscapeJSON                         // escape as a JSON string, without escaping HTML characters
+	StringEscapeRaw                          // do not escape, only enclose in double quotes
+)
+
+// BytesMode is an enum of ways to print []byte, [N]byte, and []rune values.
+// In every mode other than BytesAsElements, a nil slice is printed as nil, so that it is distinct from an empty slice.
+type BytesMode uint
+
+// Bytes modes
+const (
+	BytesAsElements BytesMode = iota // print as a slice or array of numbers
+	BytesAsString                    // print as a quoted string
+	BytesAsHex                       // print bytes as a quoted string of lower case hex digits, and runes as a quoted string
+	BytesAsBase64                    // print bytes as a quoted string of standard base64, and runes as a quoted string
+)
+
+// ColorTheme is the ANSI escape sequences that color each part of a printed value.
+// Each sequence is written before the part, followed by a reset sequence. A part with an empty sequence is not colored.
+type ColorTheme struct {
+	TypeName  string
+	FieldName string
+	String    string
+	Number    string
+	Nil       string
+	Address   string
+}
+
+// DefaultColorTheme is a theme that uses the standard ANSI colors, which most terminals support
+var DefaultColorTheme = ColorTheme{
+	TypeName:  "\x1b[36m",
+	FieldName: "\x1b[34m",
+	String:    "\x1b[32m",
+	Number:    "\x1b[33m",
+	Nil:       "\x1b[35m",
+	Address:   "\x1b[90m",
+}
+
+// colorReset is the ANSI escape sequence that resets the color
+const colorReset = "\x1b[0m"
+
+// IsTerminal returns true if the writer is a terminal, which is an *os.File that is a character device
+func IsTerminal(w io.Writer) bool {
+	f, isa := w.(*os.File)
+	if !isa {
+		return false
+	}
+
+	info, err := f.Stat()
+	return (err == nil) && (info.Mode()&os.ModeCharDevice != 0)
+}
+
 // ValueScalarPrinter prints out scalar values (bool, int, uint, float, complex, string, chan, func)
 // Prints out values similar to fmt.Sprintf("%+v"), with some exceptions:
-// - strings are optionally double quoted
+// - strings are optionally double quoted, and escaped according to StringEscapeMode
 // - chan and func values are printed as their type
 // If desired, the address can also be printed for chan, func, ptr, slice, and map values.
-// The zero value is ready to use, and will not quote strings or print addresses.
+// Numbers, strings, nil, chan and func types, and addresses are colored by the Theme.
+// The zero value is ready to use, and will not quote strings, print addresses, or color anything.
 type ValueScalarPrinter struct {
-	bldr         *strings.Builder
-	QuoteStrings bool
-	WithAddress  bool
+	bldr             *printerOutput
+	QuoteStrings     bool
+	StringEscapeMode StringEscapeMode
+	WithAddress      bool
+	Theme            ColorTheme
 }
 
 // NewValueScalarPrinter constructs a ValueScalarPrinter
@@ -24,15 +94,34 @@ func NewValueScalarPrinter() *ValueScalarPrinter {
 	return &ValueScalarPrinter{}
 }
 
+// NewValueScalarWriterPrinter constructs a ValueScalarPrinter that writes to the given writer
+func NewValueScalarWriterPrinter(w io.Writer) *ValueScalarPrinter {
+	return &ValueScalarPrinter{bldr: &printerOutput{w: w}}
+}
+
 // Init initializes scalar printer with empty string
 func (p *ValueScalarPrinter) Init() {
 	if p.bldr == nil {
-		p.bldr = &strings.Builder{}
+		p.bldr = &printerOutput{}
 	} else {
 		p.bldr.Reset()
 	}
 }
 
+// Result returns the generated string, which is empty if the printer writes to a writer
+func (p *ValueScalarPrinter) Result() string {
+	return p.bldr.String()
+}
+
+// Err returns the first error writing the last value printed to a writer, or nil if there was no error
+func (p *ValueScalarPrinter) Err() error {
+	if p.bldr == nil {
+		return nil
+	}
+
+	return p.bldr.err
+}
+
 // VisitBool prints a boolean
 func (p *ValueScalarPrinter) VisitBool(val bool) {
 	p.bldr.WriteString(strconv.FormatBool(val))
@@ -40,101 +129,139 @@ func (p *ValueScalarPrinter) VisitBool(val bool) {
 
 // VisitInt prints an int
 func (p *ValueScalarPrinter) VisitInt(val int) {
-	p.bldr.WriteString(strconv.FormatInt(int64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatInt(int64(val), 10))
 }
 
 // VisitInt8 prints an int8
 func (p *ValueScalarPrinter) VisitInt8(val int8) {
-	p.bldr.WriteString(strconv.FormatInt(int64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatInt(int64(val), 10))
 }
 
 // VisitInt16 prints an int16
 func (p *ValueScalarPrinter) VisitInt16(val int16) {
-	p.bldr.WriteString(strconv.FormatInt(int64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatInt(int64(val), 10))
 }
 
 // VisitInt32 prints an int32
 func (p *ValueScalarPrinter) VisitInt32(val int32) {
-	p.bldr.WriteString(strconv.FormatInt(int64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatInt(int64(val), 10))
 }
 
 // VisitInt64 prints an int64
 func (p *ValueScalarPrinter) VisitInt64(val int64) {
-	p.bldr.WriteString(strconv.FormatInt(val, 10))
+	p.colored(p.Theme.Number, strconv.FormatInt(val, 10))
 }
 
 // VisitUint prints a uint
 func (p *ValueScalarPrinter) VisitUint(val uint) {
-	p.bldr.WriteString(strconv.FormatUint(uint64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatUint(uint64(val), 10))
 }
 
 // VisitUint8 prints a uint8
 func (p *ValueScalarPrinter) VisitUint8(val uint8) {
-	p.bldr.WriteString(strconv.FormatUint(uint64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatUint(uint64(val), 10))
 }
 
 // VisitUint16 prints a uint16
 func (p *ValueScalarPrinter) VisitUint16(val uint16) {
-	p.bldr.WriteString(strconv.FormatUint(uint64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatUint(uint64(val), 10))
 }
 
 // VisitUint32 prints a uint32
 func (p *ValueScalarPrinter) VisitUint32(val uint32) {
-	p.bldr.WriteString(strconv.FormatUint(uint64(val), 10))
+	p.colored(p.Theme.Number, strconv.FormatUint(uint64(val), 10))
 }
 
 // VisitUint64 prints a uint64
 func (p *ValueScalarPrinter) VisitUint64(val uint64) {
-	p.bldr.WriteString(strconv.FormatUint(val, 10))
+	p.colored(p.Theme.Number, strconv.FormatUint(val, 10))
 }
 
 // VisitFloat32 prints a float32
 func (p *ValueScalarPrinter) VisitFloat32(val float32) {
-	p.bldr.WriteString(strconv.FormatFloat(float64(val), 'g', -1, 32))
+	p.colored(p.Theme.Number, strconv.FormatFloat(float64(val), 'g', -1, 32))
 }
 
 // VisitFloat64 prints a float64
 func (p *ValueScalarPrinter) VisitFloat64(val float64) {
-	p.bldr.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+	p.colored(p.Theme.Number, strconv.FormatFloat(val, 'g', -1, 64))
 }
 
 // VisitComplex64 prints a complex64
 func (p *ValueScalarPrinter) VisitComplex64(val complex64) {
-	p.bldr.WriteString(fmt.Sprint(val))
+	p.colored(p.Theme.Number, fmt.Sprint(val))
 }
 
 // VisitComplex128 prints a complex128
 func (p *ValueScalarPrinter) VisitComplex128(val complex128) {
-	p.bldr.WriteString(fmt.Sprint(val))
+	p.colored(p.Theme.Number, fmt.Sprint(val))
 }
 
 // VisitString prints a string
 func (p *ValueScalarPrinter) VisitString(val string) {
 	if p.QuoteStrings {
-		p.bldr.WriteRune('"')
-		p.bldr.WriteString(val)
-		p.bldr.WriteRune('"')
+		p.colored(p.Theme.String, quoteString(val, p.StringEscapeMode))
 	} else {
-		p.bldr.WriteString(val)
+		p.colored(p.Theme.String, val)
+	}
+}
+
+// colored prints text in the given color, or uncolored if the color is empty
+func (p *ValueScalarPrinter) colored(color, text string) {
+	if color == "" {
+		p.bldr.WriteString(text)
+		return
 	}
+
+	p.bldr.WriteString(color)
+	p.bldr.WriteString(text)
+	p.bldr.WriteString(colorReset)
+}
+
+// quoteString returns a string enclosed in double quotes, escaped according to the given mode
+func quoteString(val string, mode StringEscapeMode) string {
+	switch mode {
+	case StringEscapeJSON:
+		var (
+			bldr strings.Builder
+			enc  = json.NewEncoder(&bldr)
+		)
+		enc.SetEscapeHTML(false)
+		enc.Encode(val)
+
+		// Encode always succeeds for a string, and appends a newline
+		return strings.TrimSuffix(bldr.String(), "\n")
+
+	case StringEscapeRaw:
+		return `"` + val + `"`
+	}
+
+	return strconv.Quote(val)
 }
 
 // VisitChan prints a chan
 func (p *ValueScalarPrinter) VisitChan(val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
+	p.colored(p.Theme.TypeName, val.Type().String())
 	if p.WithAddress {
-		p.bldr.WriteString(fmt.Sprintf(" @[%p]", val.Interface()))
+		p.bldr.WriteRune(' ')
+		p.colored(p.Theme.Address, fmt.Sprintf("@[%p]", val.Interface()))
 	}
 }
 
 // VisitFunc prints a func
 func (p *ValueScalarPrinter) VisitFunc(val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
+	p.colored(p.Theme.TypeName, val.Type().String())
 	if p.WithAddress {
-		p.bldr.WriteString(fmt.Sprintf(" @[%p]", val.Interface()))
+		p.bldr.WriteRune(' ')
+		p.colored(p.Theme.Address, fmt.Sprintf("@[%p]", val.Interface()))
 	}
 }
 
+// VisitNil prints a nil
+func (p *ValueScalarPrinter) VisitNil(_ reflect.Value) {
+	p.colored(p.Theme.Nil, "nil")
+}
+
 // valueScalarPrinter is an alias
 type valueScalarPrinter struct {
 	ValueScalarPrinter
@@ -142,22 +269,119 @@ type valueScalarPrinter struct {
 
 // ValuePrinter prints out values similar to fmt.Sprintf("%+v"), with some exceptions:
 // - strings are double quoted by default
+// - []byte, [N]byte, and []rune values are optionally printed as quoted strings, hex, or base64
 // - chan and func values are printed as their type
 // - pointer values are printed with a leading & for each indirection
+// - nil pointer and interface values are printed as nil
+// - interface values are printed as the value they contain
 // - array, slice, map, and struct values are printed with same format as inline initialization
+// - components not walked due to walker limits are printed as ...(+N more), or ... for a pointer or interface
 // If desired, the address can also be printed for chan, func, pointer, slice, and map values.
 // The address is inside "@[]" in hex form, and is printed after the type.
 // In the case of multiple pointer indirections, each indirection shows the address after the &.
+//
+// Values can optionally be printed by a custom formatter registered for their type, or by their Error, String, or
+// MarshalText method, in that order of precedence. A formatted value is printed without walking into it,
+// so ValuePrinter is a ValueWalkController that skips the components of formatted values.
+// Nil values and values of unexported struct fields are never printed by a method, and a value is printed as usual
+// if the method panics or MarshalText returns an error.
+// Scalar values are only formatted if they are components of another value, as the walker does not provide the type of
+// a scalar, so a top level enum value must be wrapped in a pointer or interface to be formatted.
+//
+// Values can optionally be pretty printed, where each component of an array, slice, map, or struct is printed on a
+// separate line, indented one more level than the composite, with a trailing comma, and values of struct fields
+// aligned after the field names. A composite with no components, or that fits within an optional width when printed on
+// one line, is printed on one line.
+//
+// Values can optionally be printed as Go syntax, where strings are quoted with strconv.Quote, scalars are converted to
+// their type where the containing value does not determine it, pointers to values that are not composites are printed as
+// a call of a func literal that returns the address of a variable, nil pointers, slices, and maps are printed as nil,
+// and chan and func values are also printed as nil. Addresses are not printed. Imports returns the packages the
+// printed types refer to. Values of unexported fields or types of other packages, components not walked due to walker
+// limits, and values printed by a formatter or method may not compile.
+//
+// Type names, field names, strings, numbers, nil, and addresses can optionally be colored with the ANSI escape sequences
+// of a ColorTheme, which can be limited to when the output is written to a terminal.
+//
+// Values can optionally be redacted by struct tag, struct field name, map key, or type, and are printed as a placeholder
+// of <redacted len=N> for strings, arrays, chans, maps, and slices (following pointers and interfaces), or <redacted>
+// otherwise. Redaction takes precedence over every other way of printing, and only the length of a redacted value is
+// used. A value that may have redacted components is walked rather than printed by a formatter or method, which is a
+// value whose type contains a redacted type, a struct field whose tag or Go name is redacted, a map with string keys when
+// map keys are redacted, or an interface that may contain any of these.
+// As with formatting, a top level scalar cannot be redacted by its type.
+//
+// A ValuePrinter constructed by NewValueWriterPrinter streams output to a writer, so that printing a large value does
+// not accumulate it in memory, except that a pretty printed composite whose layout depends on all of its components is
+// held until it is closed, which is a top level array, slice, map, or struct when printing with a width, or else the
+// outermost struct being printed. Err returns the first write error of the last value printed.
 type ValuePrinter struct {
-	bldr *strings.Builder
+	bldr           *printerOutput
+	lengths        []int
+	formatted      []bool
+	next           reflect.Value
+	errors         bool
+	stringers      bool
+	textMarshalers bool
+	formatters     map[reflect.Type]func(reflect.Value) string
+	pretty         bool
+	indent         string
+	width          int
+	composites     []printerComposite
+	bytesMode      BytesMode
+	goSyntax       bool
+	typed          bool
+	ptrClosures    []bool
+	imports        map[string]bool
+	redactTag      string
+	redactFields   []*regexp.Regexp
+	redactMapKeys  []*regexp.Regexp
+	redactTypes    []TypeMatch
+	redactNext     bool
+	mapKey         reflect.Value
 	*valueScalarPrinter
 }
 
+// printerComposite is the layout of an array, slice, map, or struct being pretty printed.
+// A streamed composite is printed on multiple lines as it is walked, otherwise its layout is rewritten when it is closed.
+// A composite that holds the output releases it when it is closed.
+type printerComposite struct {
+	start      int
+	column     int
+	streamed   bool
+	holds      bool
+	components []printerComponent
+}
+
+// printerComponent is the layout of a component of an array, slice, map, or struct being pretty printed.
+// The component starts with a separator if it is not the first, followed by the field name for a struct field,
+// followed by the value.
+type printerComponent struct {
+	separator int
+	start     int
+	value     int
+	name      string
+}
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
 // NewValuePrinter constructs a ValuePrinter that does not quote strings or print addresses
 func NewValuePrinter() *ValuePrinter {
 	return &ValuePrinter{valueScalarPrinter: &valueScalarPrinter{}}
 }
 
+// NewValueWriterPrinter constructs a ValuePrinter that does not quote strings or print addresses, and writes to the
+// given writer instead of accumulating a string. Each part of a value is written as soon as it is visited,
+// so w should be buffered (eg, with bufio) if many small writes are costly.
+func NewValueWriterPrinter(w io.Writer) *ValuePrinter {
+	out := &printerOutput{w: w}
+	return &ValuePrinter{bldr: out, valueScalarPrinter: &valueScalarPrinter{ValueScalarPrinter{bldr: out}}}
+}
+
 // WithQuotedStrings is a builder method that quotes strings
 func (p *ValuePrinter) WithQuotedStrings() *ValuePrinter {
 	p.valueScalarPrinter.QuoteStrings = true
@@ -170,107 +394,1171 @@ func (p *ValuePrinter) WithAddresses() *ValuePrinter {
 	return p
 }
 
+// WithGoSyntax is a builder method that prints values as Go expressions, that can be pasted into Go code
+func (p *ValuePrinter) WithGoSyntax() *ValuePrinter {
+	p.goSyntax = true
+	return p
+}
+
+// WithStringEscapeMode is a builder method that sets how quoted strings are escaped, which is StringEscapeGo by default
+func (p *ValuePrinter) WithStringEscapeMode(mode StringEscapeMode) *ValuePrinter {
+	p.valueScalarPrinter.StringEscapeMode = mode
+	return p
+}
+
+// WithBytesMode is a builder method that sets how []byte, [N]byte, and []rune values are printed, which is
+// BytesAsElements by default
+func (p *ValuePrinter) WithBytesMode(mode BytesMode) *ValuePrinter {
+	p.bytesMode = mode
+	return p
+}
+
+// WithColorTheme is a builder method that colors the parts of printed values with the given theme.
+// Type names of Go syntax conversions and func literals are also colored, so colored Go syntax does not compile.
+func (p *ValuePrinter) WithColorTheme(theme ColorTheme) *ValuePrinter {
+	p.valueScalarPrinter.Theme = theme
+	return p
+}
+
+// WithTerminalColorTheme is a builder method that colors the parts of printed values with the given theme if the given
+// writer is a terminal, or does not color them otherwise
+func (p *ValuePrinter) WithTerminalColorTheme(w io.Writer, theme ColorTheme) *ValuePrinter {
+	if !IsTerminal(w) {
+		theme = ColorTheme{}
+	}
+
+	return p.WithColorTheme(theme)
+}
+
+// WithRedactTag is a builder method that redacts struct fields that have a tag of the given name with a redact option.
+// As is the convention for struct tags, the tag is a comma separated list of a name, which can be empty, followed by
+// options (eg, with a tag name of reflect, `reflect:",redact"` or `reflect:"name,redact"`), so the same tag can be used
+// by the walker to rename fields.
+func (p *ValuePrinter) WithRedactTag(tag string) *ValuePrinter {
+	p.redactTag = tag
+	return p
+}
+
+// WithRedactedFields is a builder method that redacts struct fields whose names, as passed to the visitor, match the
+// given pattern. Each call adds a pattern.
+func (p *ValuePrinter) WithRedactedFields(pattern *regexp.Regexp) *ValuePrinter {
+	p.redactFields = append(p.redactFields, pattern)
+	return p
+}
+
+// WithRedactedMapKeys is a builder method that redacts the values of map keys of kind string that match the given
+// pattern, including keys of an interface type that contain a string. Each call adds a pattern.
+func (p *ValuePrinter) WithRedactedMapKeys(pattern *regexp.Regexp) *ValuePrinter {
+	p.redactMapKeys = append(p.redactMapKeys, pattern)
+	return p
+}
+
+// WithRedactedTypes is a builder method that redacts values whose type matches any of the given matches.
+// Each call adds matches.
+func (p *ValuePrinter) WithRedactedTypes(matches ...TypeMatch) *ValuePrinter {
+	p.redactTypes = append(p.redactTypes, matches...)
+	return p
+}
+
+// WithIndent is a builder method that pretty prints values, indenting components by the given string for each level
+func (p *ValuePrinter) WithIndent(indent string) *ValuePrinter {
+	p.pretty = true
+	p.indent = indent
+	return p
+}
+
+// WithWidth is a builder method that pretty prints an array, slice, map, or struct on one line if it fits in the
+// given number of characters, including the indent and the text that precedes it on the line.
+// The default width of 0 prints every composite that has components on multiple lines.
+func (p *ValuePrinter) WithWidth(width int) *ValuePrinter {
+	p.width = width
+	return p
+}
+
+// WithErrors is a builder method that prints values that implement error with their Error method
+func (p *ValuePrinter) WithErrors() *ValuePrinter {
+	p.errors = true
+	return p
+}
+
+// WithStringers is a builder method that prints values that implement fmt.Stringer with their String method
+func (p *ValuePrinter) WithStringers() *ValuePrinter {
+	p.stringers = true
+	return p
+}
+
+// WithTextMarshalers is a builder method that prints values that implement encoding.TextMarshaler with their
+// MarshalText method
+func (p *ValuePrinter) WithTextMarshalers() *ValuePrinter {
+	p.textMarshalers = true
+	return p
+}
+
+// WithFormatter is a builder method that prints values of the given type with the given formatter.
+// The type passed can be a reflect.Type, or a value or reflect.Value of the type.
+// Registering a formatter for a type that already has one replaces it.
+func (p *ValuePrinter) WithFormatter(typ interface{}, formatter func(reflect.Value) string) *ValuePrinter {
+	if p.formatters == nil {
+		p.formatters = map[reflect.Type]func(reflect.Value) string{}
+	}
+
+	p.formatters[GetReflectTypeOf(typ)] = formatter
+	return p
+}
+
+// format returns the formatted string of a value and true, or false if the value is not formatted
+func (p *ValuePrinter) format(val reflect.Value) (str string, ok bool) {
+	if !val.IsValid() {
+		return
+	}
+
+	if formatter, has := p.formatters[val.Type()]; has {
+		return formatter(val), true
+	}
+
+	if !(p.errors || p.stringers || p.textMarshalers) || !val.CanInterface() {
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		if val.IsNil() {
+			return
+		}
+	}
+
+	// A method that panics is treated the same as no method
+	defer func() {
+		if recover() != nil {
+			str, ok = "", false
+		}
+	}()
+
+	switch typ := val.Type(); {
+	case p.errors && typ.Implements(errorType):
+		return val.Interface().(error).Error(), true
+
+	case p.stringers && typ.Implements(stringerType):
+		return val.Interface().(fmt.Stringer).String(), true
+
+	case p.textMarshalers && typ.Implements(textMarshalerType):
+		if text, err := val.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(text), true
+		}
+	}
+
+	return
+}
+
+// printFormatted prints the formatted string of a value, and returns true if it is formatted.
+// A value that may have redacted components is not formatted, so that it is walked instead.
+func (p *ValuePrinter) printFormatted(val reflect.Value) bool {
+	if p.redacts() && val.IsValid() {
+		typ := val.Type()
+		if (val.Kind() == reflect.Interface) && !val.IsNil() {
+			typ = val.Elem().Type()
+		}
+
+		if p.redactsWithin(typ, map[reflect.Type]bool{}) {
+			return false
+		}
+	}
+
+	str, ok := p.format(val)
+	if ok {
+		p.bldr.WriteString(str)
+	}
+
+	return ok
+}
+
+// printRedacted prints the placeholder of a value, and returns true if it is redacted.
+// The value is redacted if the component about to be visited is redacted, or if its type matches a redacted type.
+// Only the length of the value is used, so the value cannot be formatted in any way.
+func (p *ValuePrinter) printRedacted(val reflect.Value) bool {
+	redact := p.redactNext
+	p.redactNext = false
+
+	for _, match := range p.redactTypes {
+		redact = redact || (val.IsValid() && match.Matches(val.Type()))
+	}
+
+	if !redact {
+		return false
+	}
+
+	for val.IsValid() && ((val.Kind() == reflect.Ptr) || (val.Kind() == reflect.Interface)) && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+		p.bldr.WriteString(fmt.Sprintf("<redacted len=%d>", val.Len()))
+
+	default:
+		p.bldr.WriteString("<redacted>")
+	}
+
+	return true
+}
+
+// redacts returns true if any values are redacted
+func (p *ValuePrinter) redacts() bool {
+	return (p.redactTag != "") || (len(p.redactFields) > 0) || (len(p.redactMapKeys) > 0) || (len(p.redactTypes) > 0)
+}
+
+// redactsWithin returns true if a value of a type may be redacted or have redacted components.
+// An interface type may contain a value of any type, so it may have redacted components.
+// The visited map contains the types already examined, to stop a type that refers to itself.
+func (p *ValuePrinter) redactsWithin(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return false
+	}
+	visited[typ] = true
+
+	for _, match := range p.redactTypes {
+		if match.Matches(typ) {
+			return true
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Ptr, reflect.Slice:
+		return p.redactsWithin(typ.Elem(), visited)
+
+	case reflect.Interface:
+		return true
+
+	case reflect.Map:
+		return ((len(p.redactMapKeys) > 0) && (typ.Key().Kind() == reflect.String)) ||
+			p.redactsWithin(typ.Key(), visited) ||
+			p.redactsWithin(typ.Elem(), visited)
+
+	case reflect.Struct:
+		for i, n := 0, typ.NumField(); i < n; i++ {
+			if f := typ.Field(i); p.redactField(f) || p.redactsWithin(f.Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redactField returns true if a struct field is redacted by its tag or name
+func (p *ValuePrinter) redactField(fld reflect.StructField) bool {
+	if p.redactTag != "" {
+		if tag, ok := fld.Tag.Lookup(p.redactTag); ok {
+			// The first element is the name of the field, not an option
+			for _, option := range strings.Split(tag, ",")[1:] {
+				if option == "redact" {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, pattern := range p.redactFields {
+		if pattern.MatchString(fld.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactMapValue returns true if the value of a map key is redacted, which requires a key of kind string, or of an
+// interface type that contains a string
+func (p *ValuePrinter) redactMapValue(key reflect.Value) bool {
+	if (key.Kind() == reflect.Interface) && !key.IsNil() {
+		key = key.Elem()
+	}
+
+	if key.Kind() != reflect.String {
+		return false
+	}
+
+	for _, pattern := range p.redactMapKeys {
+		if pattern.MatchString(key.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatNext prints the formatted string of the component about to be visited, and returns true if it is formatted.
+// The type of the component is returned, or nil if it is not known.
+func (p *ValuePrinter) formatNext() (reflect.Type, bool) {
+	val := p.next
+	p.next = reflect.Value{}
+
+	ok := p.printRedacted(val) || p.printFormatted(val)
+	if !val.IsValid() {
+		return nil, ok
+	}
+
+	return val.Type(), ok
+}
+
+// preFormat prints the formatted string of a pointer, array, slice, map, or struct and returns true if it is formatted,
+// which includes a redacted value, a nil map or slice printed as Go syntax, and bytes printed according to the mode.
+// Every call must be matched by a call to postFormat.
+func (p *ValuePrinter) preFormat(val reflect.Value) bool {
+	p.next = reflect.Value{}
+
+	ok := true
+	switch kind := val.Kind(); {
+	case p.printRedacted(val), p.printFormatted(val):
+
+	case p.goSyntax && ((kind == reflect.Map) || (kind == reflect.Slice)) && val.IsNil():
+		// Go syntax prints nil maps and slices as nil, not as empty composites
+		p.nilLiteral(val)
+
+	case (p.bytesMode != BytesAsElements) && p.isBytes(val):
+		p.printBytes(val)
+
+	default:
+		ok = false
+	}
+	p.formatted = append(p.formatted, ok)
+
+	return ok
+}
+
+// isBytes returns true if a value is a []byte or []rune, or a [N]byte that is not printed as Go syntax,
+// as Go cannot convert a string to an array
+func (p *ValuePrinter) isBytes(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array:
+		return (val.Type().Elem().Kind() == reflect.Uint8) && !p.goSyntax
+
+	case reflect.Slice:
+		elemKind := val.Type().Elem().Kind()
+		return (elemKind == reflect.Uint8) || (elemKind == reflect.Int32)
+	}
+
+	return false
+}
+
+// printBytes prints a []byte, [N]byte, or []rune according to the bytes mode.
+// As Go syntax, it is printed as a conversion of a string of the data to the slice type, regardless of the mode.
+// A nil slice is printed as nil.
+func (p *ValuePrinter) printBytes(val reflect.Value) {
+	if (val.Kind() == reflect.Slice) && val.IsNil() {
+		p.colored(p.Theme.Nil, "nil")
+		return
+	}
+
+	var text string
+	if val.Type().Elem().Kind() == reflect.Int32 {
+		runes := make([]rune, val.Len())
+		for i := range runes {
+			runes[i] = rune(val.Index(i).Int())
+		}
+		text = string(runes)
+	} else {
+		data := make([]byte, val.Len())
+		for i := range data {
+			data[i] = byte(val.Index(i).Uint())
+		}
+
+		switch {
+		case p.goSyntax:
+			text = string(data)
+
+		case p.bytesMode == BytesAsHex:
+			text = hex.EncodeToString(data)
+
+		case p.bytesMode == BytesAsBase64:
+			text = base64.StdEncoding.EncodeToString(data)
+
+		default:
+			text = string(data)
+		}
+	}
+
+	if p.goSyntax {
+		p.colored(p.Theme.TypeName, p.typeName(val.Type()))
+		p.bldr.WriteRune('(')
+		p.colored(p.Theme.String, strconv.Quote(text))
+		p.bldr.WriteRune(')')
+		return
+	}
+
+	p.colored(p.Theme.String, quoteString(text, p.valueScalarPrinter.StringEscapeMode))
+}
+
+// preAction returns SkipChildren if the value just previsited is formatted, else Continue
+func (p *ValuePrinter) preAction() WalkAction {
+	if p.formatted[len(p.formatted)-1] {
+		return SkipChildren
+	}
+
+	return Continue
+}
+
+// postFormat returns true if the value being postvisited is formatted
+func (p *ValuePrinter) postFormat() bool {
+	ok := p.formatted[len(p.formatted)-1]
+	p.formatted = p.formatted[:len(p.formatted)-1]
+
+	return ok
+}
+
 // Init initializes printer with empty string
 func (p *ValuePrinter) Init() {
 	if p.bldr == nil {
-		p.bldr = &strings.Builder{}
+		p.bldr = &printerOutput{}
 		p.valueScalarPrinter.bldr = p.bldr
 	} else {
 		p.bldr.Reset()
 	}
+
+	p.lengths = p.lengths[:0]
+	p.formatted = p.formatted[:0]
+	p.next = reflect.Value{}
+	p.composites = p.composites[:0]
+	p.typed = false
+	p.ptrClosures = p.ptrClosures[:0]
+	p.imports = map[string]bool{}
+	p.redactNext = false
+	p.mapKey = reflect.Value{}
+}
+
+// openComposite records the start of an array, slice, map, or struct being pretty printed,
+// and prints the type and opening brace
+func (p *ValuePrinter) openComposite(length int, val reflect.Value, address bool) {
+	p.lengths = append(p.lengths, length)
+
+	if p.pretty {
+		// Without a width, every composite with components is on multiple lines, so it is printed as it is walked,
+		// except that a struct is rewritten when it is closed, to align the values after the names of the fields walked
+		comp := printerComposite{start: p.bldr.Len(), streamed: (p.width == 0) && (val.Kind() != reflect.Struct)}
+		if p.width > 0 {
+			if n := len(p.composites); n > 0 {
+				// Assume the parent is printed on multiple lines, which is the only case where the column matters
+				parent := p.composites[n-1]
+				comp.column = (n * len(p.indent)) + visibleWidth(string(p.bldr.Bytes()[parent.components[len(parent.components)-1].start:]))
+			} else {
+				// The layout of a top level composite may be rewritten until it is closed
+				comp.column = p.bldr.Column() + visibleWidth(p.bldr.String())
+				comp.holds = true
+			}
+		} else if !comp.streamed {
+			// The layout of the outermost struct may be rewritten until it is closed
+			comp.holds = true
+			for _, parent := range p.composites {
+				comp.holds = comp.holds && parent.streamed
+			}
+		}
+
+		if comp.holds {
+			p.bldr.Hold()
+		}
+		p.composites = append(p.composites, comp)
+	}
+
+	p.colored(p.Theme.TypeName, p.typeName(val.Type()))
+	if address && p.valueScalarPrinter.WithAddress && !p.goSyntax {
+		p.colored(p.Theme.Address, fmt.Sprintf("@[%p]", val.Interface()))
+	}
+	p.bldr.WriteRune('{')
+}
+
+// component prints the separator before a component and the field name of a struct field, if any
+func (p *ValuePrinter) component(separate bool, name string) {
+	p.typed = true
+	p.redactNext = false
+	if p.pretty && p.composites[len(p.composites)-1].streamed {
+		p.lineComponent(separate)
+		return
+	}
+
+	separator := p.bldr.Len()
+	if separate {
+		p.bldr.WriteString(", ")
+	}
+
+	start := p.bldr.Len()
+	if name != "" {
+		p.colored(p.Theme.FieldName, name)
+		p.bldr.WriteString(": ")
+	}
+
+	if p.pretty {
+		comp := &p.composites[len(p.composites)-1]
+		comp.components = append(comp.components, printerComponent{separator: separator, start: start, value: p.bldr.Len(), name: name})
+	}
+}
+
+// lineComponent prints the separator, newline, and indent before a component of a streamed composite
+func (p *ValuePrinter) lineComponent(separate bool) {
+	var (
+		depth = len(p.composites) - 1
+		comp  = &p.composites[depth]
+	)
+	comp.components = append(comp.components, printerComponent{})
+
+	if separate {
+		p.bldr.WriteRune(',')
+	}
+	p.bldr.WriteRune('\n')
+	p.bldr.WriteString(strings.Repeat(p.indent, depth+1))
+}
+
+// closeComposite prints the closing brace of an array, slice, map, or struct.
+// If pretty printing, a composite that is not streamed and does not fit on one line is reprinted with each component on
+// a separate line.
+func (p *ValuePrinter) closeComposite() {
+	p.lengths = p.lengths[:len(p.lengths)-1]
+
+	if !p.pretty {
+		p.bldr.WriteRune('}')
+		return
+	}
+
+	var (
+		depth = len(p.composites) - 1
+		comp  = p.composites[depth]
+	)
+	p.composites = p.composites[:depth]
+
+	if comp.streamed {
+		if len(comp.components) > 0 {
+			p.bldr.WriteString(",\n")
+			p.bldr.WriteString(strings.Repeat(p.indent, depth))
+		}
+		p.bldr.WriteRune('}')
+		return
+	}
+
+	line := p.bldr.Bytes()[comp.start:]
+	if comp.holds {
+		defer p.bldr.Release()
+	}
+
+	if (len(comp.components) == 0) ||
+		((comp.column+visibleWidth(string(line)) < p.width) && (bytes.IndexByte(line, '\n') == -1)) {
+		p.bldr.WriteRune('}')
+		return
+	}
+
+	text := string(line)
+	p.bldr.Truncate(comp.start)
+	p.bldr.WriteString(text[:comp.components[0].separator-comp.start])
+	p.bldr.WriteRune('\n')
+
+	nameWidth := 0
+	for _, c := range comp.components {
+		if len(c.name) > nameWidth {
+			nameWidth = len(c.name)
+		}
+	}
+
+	for i, c := range comp.components {
+		end := len(text)
+		if i < len(comp.components)-1 {
+			end = comp.components[i+1].separator - comp.start
+		}
+
+		p.bldr.WriteString(strings.Repeat(p.indent, depth+1))
+		if c.name != "" {
+			p.colored(p.Theme.FieldName, c.name)
+			p.bldr.WriteRune(':')
+			p.bldr.WriteString(strings.Repeat(" ", nameWidth-len(c.name)+1))
+		}
+		p.bldr.WriteString(text[c.value-comp.start : end])
+		p.bldr.WriteString(",\n")
+	}
+
+	p.bldr.WriteString(strings.Repeat(p.indent, depth))
+	p.bldr.WriteRune('}')
+}
+
+// typeName returns the name of a type, recording the packages it refers to if printing Go syntax
+func (p *ValuePrinter) typeName(typ reflect.Type) string {
+	if p.goSyntax {
+		p.addImports(typ)
+	}
+
+	return typ.String()
+}
+
+// addImports records the packages a type refers to
+func (p *ValuePrinter) addImports(typ reflect.Type) {
+	if typ.Name() != "" {
+		if typ.PkgPath() != "" {
+			p.imports[typ.PkgPath()] = true
+		}
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Ptr, reflect.Slice:
+		p.addImports(typ.Elem())
+
+	case reflect.Map:
+		p.addImports(typ.Key())
+		p.addImports(typ.Elem())
+
+	case reflect.Func:
+		for i := 0; i < typ.NumIn(); i++ {
+			p.addImports(typ.In(i))
+		}
+		for i := 0; i < typ.NumOut(); i++ {
+			p.addImports(typ.Out(i))
+		}
+
+	case reflect.Interface:
+		for i := 0; i < typ.NumMethod(); i++ {
+			p.addImports(typ.Method(i).Type)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			p.addImports(typ.Field(i).Type)
+		}
+	}
+}
+
+// literal prints the Go syntax literal of a scalar of the given type, or of the given basic type if the type is nil.
+// The literal is converted to the type, unless the type of the literal is determined by the value containing it,
+// or the literal is an untyped constant whose default type is the type.
+func (p *ValuePrinter) literal(typ reflect.Type, basic string, lit string) {
+	name := basic
+	if typ != nil {
+		name = p.typeName(typ)
+	}
+
+	color := p.Theme.Number
+	switch basic {
+	case "bool":
+		color = ""
+
+	case "string":
+		color = p.Theme.String
+	}
+
+	constant := !strings.Contains(lit, "math.")
+	if (p.typed && constant) || (name == literalDefaultType(lit, constant)) {
+		p.colored(color, lit)
+		return
+	}
+
+	p.colored(p.Theme.TypeName, name)
+	p.bldr.WriteRune('(')
+	p.colored(color, lit)
+	p.bldr.WriteRune(')')
+}
+
+// literalDefaultType returns the type of a literal printed by ValuePrinter when it is not converted to another type
+func literalDefaultType(lit string, constant bool) string {
+	switch {
+	case (lit == "true") || (lit == "false"):
+		return "bool"
+
+	case lit[0] == '"':
+		return "string"
+
+	case strings.HasPrefix(lit, "complex("):
+		return "complex128"
+
+	case !constant || strings.ContainsAny(lit, ".eE"):
+		return "float64"
+	}
+
+	return "int"
+}
+
+// floatLiteral returns the Go syntax literal of a float, which is a call to math.NaN or math.Inf if it is not a number
+func (p *ValuePrinter) floatLiteral(val float64, bitSize int) string {
+	switch {
+	case math.IsNaN(val):
+		p.imports["math"] = true
+		return "math.NaN()"
+
+	case math.IsInf(val, 1):
+		p.imports["math"] = true
+		return "math.Inf(1)"
+
+	case math.IsInf(val, -1):
+		p.imports["math"] = true
+		return "math.Inf(-1)"
+	}
+
+	return strconv.FormatFloat(val, 'g', -1, bitSize)
+}
+
+// complexLiteral returns the Go syntax literal of a complex
+func (p *ValuePrinter) complexLiteral(val complex128, bitSize int) string {
+	return "complex(" + p.floatLiteral(real(val), bitSize) + ", " + p.floatLiteral(imag(val), bitSize) + ")"
+}
+
+// nilLiteral prints a nil as Go syntax, which is converted to the type of the value it is stored in if the
+// type is not determined by the value containing it
+func (p *ValuePrinter) nilLiteral(val reflect.Value) {
+	if p.typed || !val.IsValid() || (val.Kind() == reflect.Interface) {
+		p.colored(p.Theme.Nil, "nil")
+		return
+	}
+
+	p.bldr.WriteRune('(')
+	p.colored(p.Theme.TypeName, p.typeName(val.Type()))
+	p.bldr.WriteString(")(")
+	p.colored(p.Theme.Nil, "nil")
+	p.bldr.WriteRune(')')
+}
+
+// ptrLiteral prints the start of a non-nil pointer as Go syntax.
+// A pointer to an array, struct, or non-nil slice or map is printed as & followed by the composite literal,
+// any other pointer is printed as a func literal call that returns the address of a variable.
+func (p *ValuePrinter) ptrLiteral(val reflect.Value) {
+	closure := true
+	switch elem := val.Elem(); elem.Kind() {
+	case reflect.Array, reflect.Struct:
+		closure = false
+
+	case reflect.Map, reflect.Slice:
+		closure = elem.IsNil()
+	}
+	p.ptrClosures = append(p.ptrClosures, closure)
+
+	if !closure {
+		p.bldr.WriteRune('&')
+		return
+	}
+
+	p.bldr.WriteString("func() ")
+	p.colored(p.Theme.TypeName, p.typeName(val.Type()))
+	p.bldr.WriteString(" { var v ")
+	p.colored(p.Theme.TypeName, p.typeName(val.Type().Elem()))
+	p.bldr.WriteString(" = ")
+	p.typed = true
+}
+
+// VisitBool prints a boolean, or its formatted string
+func (p *ValuePrinter) VisitBool(val bool) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "bool", strconv.FormatBool(val))
+		} else {
+			p.valueScalarPrinter.VisitBool(val)
+		}
+	}
+}
+
+// VisitInt prints an int, or its formatted string
+func (p *ValuePrinter) VisitInt(val int) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "int", strconv.FormatInt(int64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitInt(val)
+		}
+	}
+}
+
+// VisitInt8 prints an int8, or its formatted string
+func (p *ValuePrinter) VisitInt8(val int8) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "int8", strconv.FormatInt(int64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitInt8(val)
+		}
+	}
+}
+
+// VisitInt16 prints an int16, or its formatted string
+func (p *ValuePrinter) VisitInt16(val int16) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "int16", strconv.FormatInt(int64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitInt16(val)
+		}
+	}
+}
+
+// VisitInt32 prints an int32, or its formatted string
+func (p *ValuePrinter) VisitInt32(val int32) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "int32", strconv.FormatInt(int64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitInt32(val)
+		}
+	}
+}
+
+// VisitInt64 prints an int64, or its formatted string
+func (p *ValuePrinter) VisitInt64(val int64) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "int64", strconv.FormatInt(val, 10))
+		} else {
+			p.valueScalarPrinter.VisitInt64(val)
+		}
+	}
+}
+
+// VisitUint prints a uint, or its formatted string
+func (p *ValuePrinter) VisitUint(val uint) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "uint", strconv.FormatUint(uint64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitUint(val)
+		}
+	}
+}
+
+// VisitUint8 prints a uint8, or its formatted string
+func (p *ValuePrinter) VisitUint8(val uint8) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "uint8", strconv.FormatUint(uint64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitUint8(val)
+		}
+	}
+}
+
+// VisitUint16 prints a uint16, or its formatted string
+func (p *ValuePrinter) VisitUint16(val uint16) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "uint16", strconv.FormatUint(uint64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitUint16(val)
+		}
+	}
+}
+
+// VisitUint32 prints a uint32, or its formatted string
+func (p *ValuePrinter) VisitUint32(val uint32) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "uint32", strconv.FormatUint(uint64(val), 10))
+		} else {
+			p.valueScalarPrinter.VisitUint32(val)
+		}
+	}
+}
+
+// VisitUint64 prints a uint64, or its formatted string
+func (p *ValuePrinter) VisitUint64(val uint64) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "uint64", strconv.FormatUint(val, 10))
+		} else {
+			p.valueScalarPrinter.VisitUint64(val)
+		}
+	}
+}
+
+// VisitFloat32 prints a float32, or its formatted string
+func (p *ValuePrinter) VisitFloat32(val float32) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "float32", p.floatLiteral(float64(val), 32))
+		} else {
+			p.valueScalarPrinter.VisitFloat32(val)
+		}
+	}
+}
+
+// VisitFloat64 prints a float64, or its formatted string
+func (p *ValuePrinter) VisitFloat64(val float64) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "float64", p.floatLiteral(val, 64))
+		} else {
+			p.valueScalarPrinter.VisitFloat64(val)
+		}
+	}
+}
+
+// VisitComplex64 prints a complex64, or its formatted string
+func (p *ValuePrinter) VisitComplex64(val complex64) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "complex64", p.complexLiteral(complex128(val), 32))
+		} else {
+			p.valueScalarPrinter.VisitComplex64(val)
+		}
+	}
+}
+
+// VisitComplex128 prints a complex128, or its formatted string
+func (p *ValuePrinter) VisitComplex128(val complex128) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "complex128", p.complexLiteral(val, 64))
+		} else {
+			p.valueScalarPrinter.VisitComplex128(val)
+		}
+	}
+}
+
+// VisitString prints a string, or its formatted string
+func (p *ValuePrinter) VisitString(val string) {
+	if typ, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.literal(typ, "string", strconv.Quote(val))
+		} else {
+			p.valueScalarPrinter.VisitString(val)
+		}
+	}
+}
+
+// VisitChan prints a chan, or its formatted string
+func (p *ValuePrinter) VisitChan(val reflect.Value) {
+	p.next = val
+	if _, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.nilLiteral(val)
+		} else {
+			p.valueScalarPrinter.VisitChan(val)
+		}
+	}
+}
+
+// VisitFunc prints a func, or its formatted string
+func (p *ValuePrinter) VisitFunc(val reflect.Value) {
+	p.next = val
+	if _, ok := p.formatNext(); !ok {
+		if p.goSyntax {
+			p.nilLiteral(val)
+		} else {
+			p.valueScalarPrinter.VisitFunc(val)
+		}
+	}
+}
+
+// VisitNil prints a nil
+func (p *ValuePrinter) VisitNil(val reflect.Value) {
+	p.next = reflect.Value{}
+	if p.printRedacted(val) {
+		return
+	}
+
+	if p.goSyntax {
+		p.nilLiteral(val)
+	} else {
+		p.valueScalarPrinter.VisitNil(val)
+	}
 }
 
 // VisitPrePtr prints a ptr
 func (p *ValuePrinter) VisitPrePtr(val reflect.Value) {
+	if p.preFormat(val) {
+		return
+	}
+
+	p.next = val.Elem()
+	if p.goSyntax {
+		p.ptrLiteral(val)
+		return
+	}
+
 	p.bldr.WriteRune('&')
 	if p.valueScalarPrinter.WithAddress {
-		p.bldr.WriteString(fmt.Sprintf("@[%p]", val.Interface()))
+		p.colored(p.Theme.Address, fmt.Sprintf("@[%p]", val.Interface()))
 	}
 }
 
+// VisitPrePtrAction skips the value a formatted ptr points to
+func (p *ValuePrinter) VisitPrePtrAction(_ reflect.Value) WalkAction {
+	return p.preAction()
+}
+
+// VisitPostPtr completes a ptr
+func (p *ValuePrinter) VisitPostPtr(_ reflect.Value) {
+	if p.postFormat() || !p.goSyntax {
+		return
+	}
+
+	closure := p.ptrClosures[len(p.ptrClosures)-1]
+	p.ptrClosures = p.ptrClosures[:len(p.ptrClosures)-1]
+	if closure {
+		p.bldr.WriteString("; return &v }()")
+	}
+}
+
+// VisitPreInterface prepares to print the value an interface contains
+func (p *ValuePrinter) VisitPreInterface(val reflect.Value) {
+	p.next = val.Elem()
+	p.typed = false
+}
+
 // VisitPreArray prints an array
-func (p *ValuePrinter) VisitPreArray(_ int, val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
-	p.bldr.WriteRune('{')
+func (p *ValuePrinter) VisitPreArray(length int, val reflect.Value) {
+	if p.preFormat(val) {
+		return
+	}
+
+	p.openComposite(length, val, false)
+}
+
+// VisitPreArrayAction skips the elements of a formatted array
+func (p *ValuePrinter) VisitPreArrayAction(_ int, _ reflect.Value) WalkAction {
+	return p.preAction()
 }
 
 // VisitPreArrayIndex prints a value of a array
-func (p *ValuePrinter) VisitPreArrayIndex(_ int, idx int, _ reflect.Value) {
-	if idx > 0 {
-		p.bldr.WriteString(", ")
-	}
+func (p *ValuePrinter) VisitPreArrayIndex(_ int, idx int, val reflect.Value) {
+	p.component(idx > 0, "")
+	p.next = val
 }
 
 // VisitPostArray prints an array
 func (p *ValuePrinter) VisitPostArray(_ int, _ reflect.Value) {
-	p.bldr.WriteRune('}')
+	if p.postFormat() {
+		return
+	}
+
+	p.closeComposite()
 }
 
 // VisitPreSlice prints a slice
-func (p *ValuePrinter) VisitPreSlice(_ int, val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
-	if p.valueScalarPrinter.WithAddress {
-		p.bldr.WriteString(fmt.Sprintf("@[%p]", val.Interface()))
+func (p *ValuePrinter) VisitPreSlice(length int, val reflect.Value) {
+	if p.preFormat(val) {
+		return
 	}
-	p.bldr.WriteRune('{')
+
+	p.openComposite(length, val, true)
+}
+
+// VisitPreSliceAction skips the elements of a formatted slice
+func (p *ValuePrinter) VisitPreSliceAction(_ int, _ reflect.Value) WalkAction {
+	return p.preAction()
 }
 
 // VisitPreSliceIndex prints a value of a slice
-func (p *ValuePrinter) VisitPreSliceIndex(_ int, idx int, _ reflect.Value) {
-	if idx > 0 {
-		p.bldr.WriteString(", ")
-	}
+func (p *ValuePrinter) VisitPreSliceIndex(_ int, idx int, val reflect.Value) {
+	p.component(idx > 0, "")
+	p.next = val
 }
 
 // VisitPostSlice prints a slice
 func (p *ValuePrinter) VisitPostSlice(_ int, _ reflect.Value) {
-	p.bldr.WriteRune('}')
+	if p.postFormat() {
+		return
+	}
+
+	p.closeComposite()
 }
 
 // VisitPreMap prints a map
-func (p *ValuePrinter) VisitPreMap(_ int, val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
-	if p.valueScalarPrinter.WithAddress {
-		p.bldr.WriteString(fmt.Sprintf("@[%p]", val.Interface()))
+func (p *ValuePrinter) VisitPreMap(length int, val reflect.Value) {
+	if p.preFormat(val) {
+		return
 	}
-	p.bldr.WriteRune('{')
+
+	p.openComposite(length, val, true)
+}
+
+// VisitPreMapAction skips the keys and values of a formatted map
+func (p *ValuePrinter) VisitPreMapAction(_ int, _ reflect.Value) WalkAction {
+	return p.preAction()
 }
 
 // VisitPreMapKey prints a key of a map
-func (p *ValuePrinter) VisitPreMapKey(_ int, idx int, _ reflect.Value) {
-	if idx > 0 {
-		p.bldr.WriteString(", ")
-	}
+func (p *ValuePrinter) VisitPreMapKey(_ int, idx int, val reflect.Value) {
+	p.component(idx > 0, "")
+	p.next = val
+	p.mapKey = val
 }
 
 // VisitPreMapValue prints a value of a map key
-func (p *ValuePrinter) VisitPreMapValue(_ int, _ int, _ reflect.Value) {
+func (p *ValuePrinter) VisitPreMapValue(_ int, _ int, val reflect.Value) {
 	p.bldr.WriteString(": ")
+	p.next = val
+	p.redactNext = p.redactMapValue(p.mapKey)
 }
 
 // VisitPostMap prints a map
 func (p *ValuePrinter) VisitPostMap(_ int, _ reflect.Value) {
-	p.bldr.WriteRune('}')
+	if p.postFormat() {
+		return
+	}
+
+	p.closeComposite()
 }
 
 // VisitPreStruct prints a struct
-func (p *ValuePrinter) VisitPreStruct(_ int, val reflect.Value) {
-	p.bldr.WriteString(val.Type().String())
-	p.bldr.WriteRune('{')
+func (p *ValuePrinter) VisitPreStruct(length int, val reflect.Value) {
+	if p.preFormat(val) {
+		return
+	}
+
+	p.openComposite(length, val, false)
+}
+
+// VisitPreStructAction skips the fields of a formatted struct
+func (p *ValuePrinter) VisitPreStructAction(_ int, _ reflect.Value) WalkAction {
+	return p.preAction()
 }
 
 // VisitPreStructFieldValue prints the value of a struct field
-func (p *ValuePrinter) VisitPreStructFieldValue(_ int, idx int, fld reflect.StructField, _ reflect.Value) {
-	if idx > 0 {
-		p.bldr.WriteString(", ")
-	}
-	p.bldr.WriteString(fld.Name)
-	p.bldr.WriteString(": ")
+func (p *ValuePrinter) VisitPreStructFieldValue(_ int, idx int, fld reflect.StructField, val reflect.Value) {
+	p.component(idx > 0, fld.Name)
+	p.next = val
+	p.redactNext = p.redactField(fld)
 }
 
 // VisitPostStruct prints a struct
 func (p *ValuePrinter) VisitPostStruct(_ int, _ reflect.Value) {
-	p.bldr.WriteRune('}')
+	if p.postFormat() {
+		return
+	}
+
+	p.closeComposite()
+}
+
+// VisitTruncated prints components that were not walked
+func (p *ValuePrinter) VisitTruncated(kind reflect.Kind, remaining int) {
+	switch kind {
+	case reflect.Ptr, reflect.Interface:
+		p.bldr.WriteString("...")
+
+	default:
+		p.component(remaining < p.lengths[len(p.lengths)-1], "")
+		p.bldr.WriteString("...(+")
+		p.bldr.WriteString(groupDigits(remaining))
+		p.bldr.WriteString(" more)")
+	}
+}
+
+// groupDigits formats a non-negative int with a comma between each group of three digits
+func groupDigits(n int) string {
+	var (
+		digits = strconv.Itoa(n)
+		bldr   strings.Builder
+	)
+
+	for i, d := range digits {
+		if (i > 0) && ((len(digits)-i)%3 == 0) {
+			bldr.WriteRune(',')
+		}
+		bldr.WriteRune(d)
+	}
+
+	return bldr.String()
+}
+
+// Imports returns the sorted import paths of the packages of the named types printed with Go syntax,
+// and the math package if a float is printed as math.NaN() or math.Inf(). It is empty if Go syntax is not printed.
+func (p *ValuePrinter) Imports() []string {
+	imports := make([]string, 0, len(p.imports))
+	for path := range p.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+
+	return imports
 }
 
 // Result returns the generated string
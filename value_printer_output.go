@@ -0,0 +1,125 @@
+package goreflect
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// printerOutput is the output of a printer, which is either accumulated in a buffer, or streamed to a writer.
+// When streaming, output is held in the buffer while it may still be rewritten, which is while a pretty printed
+// composite is being laid out, and the first write error is kept, after which all output is discarded.
+type printerOutput struct {
+	buf    bytes.Buffer
+	w      io.Writer
+	err    error
+	hold   bool
+	column int
+}
+
+// WriteString writes a string
+func (o *printerOutput) WriteString(s string) {
+	if (o.w == nil) || o.hold {
+		o.buf.WriteString(s)
+		return
+	}
+
+	o.write(s)
+}
+
+// WriteRune writes a rune
+func (o *printerOutput) WriteRune(r rune) {
+	if (o.w == nil) || o.hold {
+		o.buf.WriteRune(r)
+		return
+	}
+
+	o.write(string(r))
+}
+
+// write writes a string to the writer, tracking the column the next string starts at
+func (o *printerOutput) write(s string) {
+	if o.err != nil {
+		return
+	}
+
+	if _, o.err = io.WriteString(o.w, s); o.err != nil {
+		return
+	}
+
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		o.column = visibleWidth(s[i+1:])
+	} else {
+		o.column += visibleWidth(s)
+	}
+}
+
+// Len returns the number of bytes held in the buffer
+func (o *printerOutput) Len() int {
+	return o.buf.Len()
+}
+
+// Bytes returns the bytes held in the buffer
+func (o *printerOutput) Bytes() []byte {
+	return o.buf.Bytes()
+}
+
+// Truncate discards all but the first n bytes held in the buffer
+func (o *printerOutput) Truncate(n int) {
+	o.buf.Truncate(n)
+}
+
+// String returns the contents of the buffer, which is empty when streaming
+func (o *printerOutput) String() string {
+	return o.buf.String()
+}
+
+// Column returns the number of visible characters after the last newline that have been streamed,
+// which is always 0 when not streaming, as the buffer contains all output
+func (o *printerOutput) Column() int {
+	return o.column
+}
+
+// Hold holds output in the buffer until Release is called, if streaming
+func (o *printerOutput) Hold() {
+	o.hold = true
+}
+
+// Release streams the output held in the buffer, if streaming
+func (o *printerOutput) Release() {
+	if o.w == nil {
+		return
+	}
+
+	o.hold = false
+	o.write(o.buf.String())
+	o.buf.Reset()
+}
+
+// Reset empties the buffer and clears any write error, but keeps the writer
+func (o *printerOutput) Reset() {
+	o.buf.Reset()
+	o.err = nil
+	o.hold = false
+	o.column = 0
+}
+
+// visibleWidth returns the number of runes in printed text, excluding ANSI escape sequences
+func visibleWidth(text string) int {
+	width := 0
+	for i := 0; i < len(text); {
+		if text[i] == '\x1b' {
+			for i++; (i < len(text)) && (text[i] != 'm'); i++ {
+			}
+			i++
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		width++
+	}
+
+	return width
+}
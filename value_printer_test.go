@@ -1,9 +1,19 @@
 package goreflect
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"go/parser"
+	"math"
+	"math/big"
+	"net"
+	"os"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +39,13 @@ func TestValuePrinter(t *testing.T) {
 			Bar: 10,
 		}
 
+		stif = struct {
+			Foo interface{}
+			Bar error
+		}{
+			Foo: 5,
+		}
+
 		ptrptr = &ptr
 		arrarr = [2][3]int{{11, 12, 13}, {14, 15, 16}}
 		slsl   = [][]int{{17, 18, 19}, {20, 21, 22}}
@@ -168,6 +185,18 @@ func TestValuePrinter(t *testing.T) {
 	wp.Walk(st)
 	assert.Equal(t, `struct { Foo string; Bar int }{Foo: "fooish", Bar: 10}`, pp.Result())
 
+	// Nil
+	w.Walk(nil)
+	assert.Equal(t, "nil", p.Result())
+	wp.Walk((*int)(nil))
+	assert.Equal(t, "nil", pp.Result())
+
+	// Interface
+	w.Walk(stif)
+	assert.Equal(t, "struct { Foo interface {}; Bar error }{Foo: 5, Bar: nil}", p.Result())
+	wp.Walk(stif)
+	assert.Equal(t, "struct { Foo interface {}; Bar error }{Foo: 5, Bar: nil}", pp.Result())
+
 	// PtrPtr
 	w.Walk(ptrptr)
 	assert.Equal(t, "&&5", p.Result())
@@ -185,4 +214,747 @@ func TestValuePrinter(t *testing.T) {
 	assert.Equal(t, "[][]int{[]int{17, 18, 19}, []int{20, 21, 22}}", p.Result())
 	wp.Walk(slsl)
 	assert.Equal(t, fmt.Sprintf("[][]int@[%p]{[]int@[%p]{17, 18, 19}, []int@[%p]{20, 21, 22}}", slsl, slsl[0], slsl[1]), pp.Result())
+
+	// Truncated
+	w.WithMaxElements(2)
+	w.Walk(make([]int, 10000))
+	assert.Equal(t, "[]int{0, 0, ...(+9,998 more)}", p.Result())
+	w.Walk(map[int]int{})
+	assert.Equal(t, "map[int]int{}", p.Result())
+
+	w.WithoutMaxElements()
+	w.WithMaxDepth(2)
+	w.Walk(st)
+	assert.Equal(t, "struct { Foo string; Bar int }{Foo: fooish, Bar: 10}", p.Result())
+	w.Walk(arrarr)
+	assert.Equal(t, "[2][3]int{[3]int{...(+3 more)}, [3]int{...(+3 more)}}", p.Result())
+	w.Walk(ptrptr)
+	assert.Equal(t, "&&...", p.Result())
+	w.Walk(stif)
+	assert.Equal(t, "struct { Foo interface {}; Bar error }{Foo: ..., Bar: nil}", p.Result())
+
+	w.WithoutMaxDepth()
+	w.WithMaxNodes(3)
+	w.Walk(slsl)
+	assert.Equal(t, "[][]int{[]int{17, ...(+2 more)}, ...(+1 more)}", p.Result())
+}
+
+type printerTestColor int
+
+const (
+	printerTestRed printerTestColor = iota
+	printerTestGreen
+)
+
+func (c printerTestColor) String() string {
+	return [...]string{"Red", "Green"}[c]
+}
+
+type printerTestError struct {
+	Code int
+}
+
+func (e printerTestError) Error() string {
+	return fmt.Sprintf("error %d", e.Code)
+}
+
+func (e printerTestError) String() string {
+	return "not printed"
+}
+
+type printerTestEmbedded struct {
+	LongerField int
+}
+
+type printerTestName struct {
+	First, Last string
+}
+
+func (n printerTestName) MarshalText() ([]byte, error) {
+	if n.Last == "" {
+		return nil, fmt.Errorf("no last name")
+	}
+
+	return []byte(n.Last + ", " + n.First), nil
+}
+
+func TestValuePrinterFormats(t *testing.T) {
+	var (
+		p   = NewValuePrinter()
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		tm  = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		red = printerTestRed
+		val = struct {
+			Colors []printerTestColor
+			Err    error
+			Name   printerTestName
+			When   time.Time
+			IP     net.IP
+			Amount *big.Int
+			color  printerTestColor
+		}{
+			Colors: []printerTestColor{printerTestRed, printerTestGreen},
+			Err:    printerTestError{Code: 1},
+			Name:   printerTestName{First: "Jane", Last: "Doe"},
+			When:   tm,
+			IP:     net.IPv4(1, 2, 3, 4),
+			Amount: big.NewInt(1000),
+			color:  printerTestGreen,
+		}
+	)
+
+	// Nothing is formatted by default
+	w.Walk(val.Colors)
+	assert.Equal(t, "[]goreflect.printerTestColor{0, 1}", p.Result())
+
+	// Errors only
+	p.WithErrors()
+	w.Walk([]interface{}{val.Err, val.Colors[1]})
+	assert.Equal(t, "[]interface {}{error 1, 1}", p.Result())
+
+	// Errors take precedence over Stringers, a top level scalar is not formatted, but a pointer to one is
+	p.WithStringers()
+	w.Walk([]interface{}{val.Err, val.Colors[1]})
+	assert.Equal(t, "[]interface {}{error 1, Green}", p.Result())
+	w.Walk(printerTestGreen)
+	assert.Equal(t, "1", p.Result())
+	w.Walk(&red)
+	assert.Equal(t, "Red", p.Result())
+	w.Walk(reflect.ValueOf(&val.IP).Elem())
+	assert.Equal(t, "1.2.3.4", p.Result())
+
+	// TextMarshalers, unless MarshalText fails
+	p.WithTextMarshalers()
+	w.WithSortedMapKeys()
+	w.Walk(map[string]printerTestName{"a": val.Name, "b": {First: "John"}})
+	assert.Equal(t, "map[string]goreflect.printerTestName{a: Doe, Jane, b: goreflect.printerTestName{First: John, Last: }}", p.Result())
+
+	// Components of formatted values are not walked, unexported fields are not formatted by methods
+	w.Walk(val)
+	assert.Equal(
+		t,
+		"struct { Colors []goreflect.printerTestColor; Err error; Name goreflect.printerTestName; When time.Time; IP net.IP; Amount *big.Int; color goreflect.printerTestColor }"+
+			"{Colors: []goreflect.printerTestColor{Red, Green}, Err: error 1, Name: Doe, Jane, When: 2020-01-02 03:04:05 +0000 UTC, IP: 1.2.3.4, Amount: 1000, color: 1}",
+		p.Result(),
+	)
+
+	// Nil values are not formatted, and a method that panics is treated the same as no method
+	var nilInt *big.Int
+	w.Walk([]interface{}{nilInt, printerTestColor(5)})
+	assert.Equal(t, "[]interface {}{nil, 5}", p.Result())
+
+	// Custom formatters take precedence, and apply to unexported fields
+	p.WithFormatter(printerTestRed, func(v reflect.Value) string {
+		return strings.ToLower(printerTestColor(v.Int()).String())
+	})
+	p.WithFormatter(reflect.TypeOf(tm), func(v reflect.Value) string {
+		return v.Interface().(time.Time).Format("2006-01-02")
+	})
+	w.Walk(struct {
+		When  time.Time
+		color printerTestColor
+	}{When: tm, color: printerTestGreen})
+	assert.Equal(t, "struct { When time.Time; color goreflect.printerTestColor }{When: 2020-01-02, color: green}", p.Result())
+
+	// A formatted value inside a truncated walk
+	w.WithMaxElements(1)
+	w.Walk(val.Colors)
+	assert.Equal(t, "[]goreflect.printerTestColor{red, ...(+1 more)}", p.Result())
+}
+
+func TestValuePrinterPretty(t *testing.T) {
+	var (
+		p   = NewValuePrinter().WithIndent("  ")
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		val = map[string][]walkerTestItem{
+			"a": {{Name: "x"}, {Name: "yy"}},
+			"b": {},
+		}
+		st = struct {
+			ID          int
+			Description string
+			Tags        []string
+		}{ID: 1, Description: "desc", Tags: []string{"t"}}
+	)
+
+	// Scalars are unaffected
+	w.Walk(1)
+	assert.Equal(t, "1", p.Result())
+
+	// Every composite with components is on multiple lines by default
+	w.WithSortedMapKeys()
+	w.Walk(val)
+	assert.Equal(
+		t,
+		`map[string][]goreflect.walkerTestItem{
+  a: []goreflect.walkerTestItem{
+    goreflect.walkerTestItem{
+      Name:  x,
+      Price: nil,
+    },
+    goreflect.walkerTestItem{
+      Name:  yy,
+      Price: nil,
+    },
+  },
+  b: []goreflect.walkerTestItem{},
+}`,
+		p.Result(),
+	)
+
+	// Struct field values are aligned
+	w.Walk(&st)
+	assert.Equal(
+		t,
+		`&struct { ID int; Description string; Tags []string }{
+  ID:          1,
+  Description: desc,
+  Tags:        []string{
+    t,
+  },
+}`,
+		p.Result(),
+	)
+
+	// Composites that fit in the width stay on one line, counting the indent and preceding text
+	p.WithWidth(50)
+	w.Walk(val)
+	assert.Equal(
+		t,
+		`map[string][]goreflect.walkerTestItem{
+  a: []goreflect.walkerTestItem{
+    goreflect.walkerTestItem{Name: x, Price: nil},
+    goreflect.walkerTestItem{Name: yy, Price: nil},
+  },
+  b: []goreflect.walkerTestItem{},
+}`,
+		p.Result(),
+	)
+
+	p.WithWidth(45)
+	w.Walk([]int{1, 2, 3})
+	assert.Equal(t, "[]int{1, 2, 3}", p.Result())
+	w.Walk([]walkerTestItem{{Name: "x"}})
+	assert.Equal(
+		t,
+		`[]goreflect.walkerTestItem{
+  goreflect.walkerTestItem{
+    Name:  x,
+    Price: nil,
+  },
+}`,
+		p.Result(),
+	)
+
+	// Struct field values are aligned after the names of the fields walked, with or without a width
+	w.WithPromotedFields()
+	w.WithExportedFieldsOnly()
+	w.WithStructTag("reflect")
+	for _, width := range []int{45, 0} {
+		p.WithWidth(width)
+		w.Walk(struct {
+			ID                 int `reflect:"identifier"`
+			unexportedLongName int
+			printerTestEmbedded
+		}{})
+		assert.Equal(
+			t,
+			`struct { ID int "reflect:\"identifier\""; unexportedLongName int; goreflect.printerTestEmbedded }{
+  identifier:  0,
+  LongerField: 0,
+}`,
+			p.Result(),
+		)
+	}
+
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	p.WithWidth(45)
+
+	// Truncated components are on their own line
+	p.WithWidth(0)
+	w.WithMaxElements(2)
+	w.Walk([]int{1, 2, 3})
+	assert.Equal(
+		t,
+		`[]int{
+  1,
+  2,
+  ...(+1 more),
+}`,
+		p.Result(),
+	)
+}
+
+func TestValuePrinterGoSyntax(t *testing.T) {
+	var (
+		p   = NewValuePrinter().WithGoSyntax().WithAddresses()
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		i8  = int8(3)
+		str = "a"
+		val = struct {
+			Int8   int8
+			Float  float32
+			Str    string
+			Ptr    *int8
+			PtrPtr **string
+			Any    []interface{}
+			Map    map[string]printerTestColor
+			Items  *[]walkerTestItem
+			NilMap map[int]int
+			Fn     func()
+		}{
+			Int8:   3,
+			Float:  float32(math.Inf(1)),
+			Str:    "say \"hi\"\n",
+			Ptr:    &i8,
+			PtrPtr: func() **string { v := &str; return &v }(),
+			Any:    []interface{}{1, int8(2), 1.5, 2.0, "s", printerTestGreen, (*int)(nil), nil, complex64(1 + 2i), []big.Word(nil)},
+			Map:    map[string]printerTestColor{"r": printerTestRed},
+			Items:  &[]walkerTestItem{{Name: "x"}},
+		}
+	)
+
+	// Top level scalars are converted to their type when it is not the default type of the literal
+	w.Walk(i8)
+	assert.Equal(t, "int8(3)", p.Result())
+	w.Walk(str)
+	assert.Equal(t, `"a"`, p.Result())
+	w.Walk(math.NaN())
+	assert.Equal(t, "math.NaN()", p.Result())
+	assert.Equal(t, []string{"math"}, p.Imports())
+	w.Walk(&i8)
+	assert.Equal(t, "func() *int8 { var v int8 = 3; return &v }()", p.Result())
+	w.Walk((*int)(nil))
+	assert.Equal(t, "(*int)(nil)", p.Result())
+
+	w.Walk(val)
+	printed := p.Result()
+	assert.Equal(
+		t,
+		"struct { Int8 int8; Float float32; Str string; Ptr *int8; PtrPtr **string; Any []interface {}; Map map[string]goreflect.printerTestColor; Items *[]goreflect.walkerTestItem; NilMap map[int]int; Fn func() }{"+
+			"Int8: 3, "+
+			"Float: float32(math.Inf(1)), "+
+			`Str: "say \"hi\"\n", `+
+			"Ptr: func() *int8 { var v int8 = 3; return &v }(), "+
+			`PtrPtr: func() **string { var v *string = func() *string { var v string = "a"; return &v }(); return &v }(), `+
+			`Any: []interface {}{1, int8(2), 1.5, float64(2), "s", goreflect.printerTestColor(1), (*int)(nil), nil, complex64(complex(1, 2)), ([]big.Word)(nil)}, `+
+			`Map: map[string]goreflect.printerTestColor{"r": 0}, `+
+			`Items: &[]goreflect.walkerTestItem{goreflect.walkerTestItem{Name: "x", Price: nil}}, `+
+			"NilMap: nil, "+
+			"Fn: nil}",
+		printed,
+	)
+	assert.Equal(t, []string{"github.com/bantling/goreflect", "math", "math/big"}, p.Imports())
+
+	_, err := parser.ParseExpr(printed)
+	assert.Nil(t, err)
+
+	// Pretty printed Go syntax
+	p.WithIndent("\t")
+	w.Walk(map[string]walkerTestItem{"a": {Name: "x"}})
+	assert.Equal(t, "map[string]goreflect.walkerTestItem{\n\t\"a\": goreflect.walkerTestItem{\n\t\tName:  \"x\",\n\t\tPrice: nil,\n\t},\n}", p.Result())
+
+	// Imports are empty if Go syntax is not printed
+	p = NewValuePrinter()
+	NewValueDepthFirstWalker(NewValueVisitorAdapter(p)).Walk(val)
+	assert.Equal(t, []string{}, p.Imports())
+}
+
+func TestValuePrinterStrings(t *testing.T) {
+	var (
+		sp  = NewValueScalarPrinter()
+		str = "say \"<hi>\"\n\xff"
+	)
+
+	// Strings are escaped as Go by default
+	sp.Init()
+	sp.QuoteStrings = true
+	sp.VisitString(str)
+	assert.Equal(t, `"say \"<hi>\"\n\xff"`, sp.bldr.String())
+
+	sp.Init()
+	sp.StringEscapeMode = StringEscapeJSON
+	sp.VisitString(str)
+	assert.Equal(t, `"say \"<hi>\"\n�"`, sp.bldr.String())
+
+	sp.Init()
+	sp.StringEscapeMode = StringEscapeRaw
+	sp.VisitString(str)
+	assert.Equal(t, "\""+str+"\"", sp.bldr.String())
+
+	// Unquoted strings are never escaped
+	sp.Init()
+	sp.QuoteStrings = false
+	sp.VisitString(str)
+	assert.Equal(t, str, sp.bldr.String())
+
+	var (
+		p   = NewValuePrinter()
+		w   = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		val = struct {
+			Bytes []byte
+			Array [3]byte
+			Runes []rune
+			IP    net.IP
+		}{
+			Bytes: []byte("a\tb"),
+			Array: [3]byte{'x', 'y', 'z'},
+			Runes: []rune("π\n"),
+			IP:    net.IP{127, 0, 0, 1},
+		}
+	)
+
+	// Bytes are printed as elements by default
+	w.Walk(val.Bytes)
+	assert.Equal(t, "[]uint8{97, 9, 98}", p.Result())
+
+	p.WithBytesMode(BytesAsString)
+	w.Walk(val)
+	assert.Equal(t, `struct { Bytes []uint8; Array [3]uint8; Runes []int32; IP net.IP }{Bytes: "a\tb", Array: "xyz", Runes: "π\n", IP: "\x7f\x00\x00\x01"}`, p.Result())
+
+	p.WithBytesMode(BytesAsHex).WithStringEscapeMode(StringEscapeJSON)
+	w.Walk(val)
+	assert.Equal(t, `struct { Bytes []uint8; Array [3]uint8; Runes []int32; IP net.IP }{Bytes: "610962", Array: "78797a", Runes: "π\n", IP: "7f000001"}`, p.Result())
+
+	// Nil slices are distinct from empty slices
+	for _, mode := range []BytesMode{BytesAsString, BytesAsHex, BytesAsBase64} {
+		p.WithBytesMode(mode)
+		w.Walk(struct {
+			Nil, Empty []byte
+			NilRunes   []rune
+			EmptyRunes []rune
+		}{Empty: []byte{}, EmptyRunes: []rune{}})
+		assert.Equal(t, `struct { Nil []uint8; Empty []uint8; NilRunes []int32; EmptyRunes []int32 }{Nil: nil, Empty: "", NilRunes: nil, EmptyRunes: ""}`, p.Result())
+	}
+
+	// Methods take precedence over the bytes mode
+	p.WithBytesMode(BytesAsBase64).WithStringers()
+	w.Walk(val)
+	assert.Equal(t, `struct { Bytes []uint8; Array [3]uint8; Runes []int32; IP net.IP }{Bytes: "YQli", Array: "eHl6", Runes: "π\n", IP: 127.0.0.1}`, p.Result())
+
+	// Go syntax converts the data of slices, and prints arrays as elements
+	p = NewValuePrinter().WithGoSyntax().WithBytesMode(BytesAsBase64)
+	NewValueDepthFirstWalker(NewValueVisitorAdapter(p)).Walk(val)
+	assert.Equal(t, `struct { Bytes []uint8; Array [3]uint8; Runes []int32; IP net.IP }{Bytes: []uint8("a\tb"), Array: [3]uint8{120, 121, 122}, Runes: []int32("π\n"), IP: net.IP("\x7f\x00\x00\x01")}`, p.Result())
+}
+
+func TestValuePrinterColors(t *testing.T) {
+	var (
+		p = NewValuePrinter().WithQuotedStrings().WithColorTheme(DefaultColorTheme)
+		w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		c = func(color, text string) string {
+			return color + text + colorReset
+		}
+		th  = DefaultColorTheme
+		i   = 1
+		val = struct {
+			Name  string
+			Count *int
+			Any   interface{}
+		}{Name: "a", Count: &i}
+	)
+
+	w.Walk(val)
+	assert.Equal(
+		t,
+		c(th.TypeName, "struct { Name string; Count *int; Any interface {} }")+"{"+
+			c(th.FieldName, "Name")+": "+c(th.String, `"a"`)+", "+
+			c(th.FieldName, "Count")+": &"+c(th.Number, "1")+", "+
+			c(th.FieldName, "Any")+": "+c(th.Nil, "nil")+"}",
+		p.Result(),
+	)
+
+	// Addresses
+	p.WithAddresses()
+	w.Walk(&i)
+	assert.Equal(t, "&"+c(th.Address, fmt.Sprintf("@[%p]", &i))+c(th.Number, "1"), p.Result())
+
+	// Pretty printing aligns and measures the text without the escape sequences
+	p = NewValuePrinter().WithColorTheme(th).WithIndent(" ").WithWidth(30)
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	w.Walk(walkerTestItem{Name: "a"})
+	assert.Equal(
+		t,
+		c(th.TypeName, "goreflect.walkerTestItem")+"{\n "+
+			c(th.FieldName, "Name")+":  "+c(th.String, "a")+",\n "+
+			c(th.FieldName, "Price")+": "+c(th.Nil, "nil")+",\n}",
+		p.Result(),
+	)
+	w.Walk([]int{1, 2})
+	assert.Equal(t, c(th.TypeName, "[]int")+"{"+c(th.Number, "1")+", "+c(th.Number, "2")+"}", p.Result())
+
+	// Go syntax conversions
+	p = NewValuePrinter().WithGoSyntax().WithColorTheme(th)
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	w.Walk([]interface{}{int8(1), (*int)(nil)})
+	assert.Equal(
+		t,
+		c(th.TypeName, "[]interface {}")+"{"+
+			c(th.TypeName, "int8")+"("+c(th.Number, "1")+"), "+
+			"("+c(th.TypeName, "*int")+")("+c(th.Nil, "nil")+")}",
+		p.Result(),
+	)
+
+	// Only terminals are colored
+	var buf bytes.Buffer
+	assert.False(t, IsTerminal(&buf))
+	assert.False(t, IsTerminal(os.NewFile(^uintptr(0), "invalid")))
+
+	p = NewValuePrinter().WithTerminalColorTheme(&buf, th)
+	NewValueDepthFirstWalker(NewValueVisitorAdapter(p)).Walk([]int{1})
+	assert.Equal(t, "[]int{1}", p.Result())
+}
+
+// printerTestWriter records each write, and fails once a limit is reached
+type printerTestWriter struct {
+	writes []string
+	limit  int
+}
+
+func (w *printerTestWriter) Write(data []byte) (int, error) {
+	if len(w.writes) == w.limit {
+		return 0, fmt.Errorf("write limit reached")
+	}
+
+	w.writes = append(w.writes, string(data))
+	return len(data), nil
+}
+
+func TestValueWriterPrinter(t *testing.T) {
+	var (
+		val = map[string][]walkerTestItem{
+			"a": {{Name: "x"}, {Name: "yy"}},
+			"b": {},
+		}
+		p = NewValuePrinter()
+		w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	)
+
+	w.WithSortedMapKeys()
+
+	w.Walk(val)
+	printed := p.Result()
+
+	// Output is streamed as each part is visited
+	var (
+		tw = &printerTestWriter{limit: -1}
+		wp = NewValueWriterPrinter(tw)
+		ww = NewValueDepthFirstWalker(NewValueVisitorAdapter(wp))
+	)
+
+	ww.WithSortedMapKeys()
+
+	ww.Walk(val)
+	assert.Nil(t, wp.Err())
+	assert.Equal(t, "", wp.Result())
+	assert.Equal(t, printed, strings.Join(tw.writes, ""))
+	assert.Equal(t, []string{"map[string][]goreflect.walkerTestItem", "{", "a", ": "}, tw.writes[:4])
+
+	// Buffered output, which is pretty printed, holding each top level composite until it is closed
+	var (
+		buf bytes.Buffer
+		bw  = bufio.NewWriter(&buf)
+	)
+
+	p.WithIndent("  ").WithWidth(50)
+	w.Walk(val)
+	printed = p.Result()
+
+	wp = NewValueWriterPrinter(bw).WithIndent("  ").WithWidth(50)
+	ww.WithVisitor(NewValueVisitorAdapter(wp))
+	ww.Walk(val)
+	assert.Nil(t, wp.Err())
+	assert.Nil(t, bw.Flush())
+	assert.Equal(t, printed, buf.String())
+
+	// Without a width, pretty printed output is streamed as it is walked, before the composite is closed
+	p.WithWidth(0)
+	w.Walk(val)
+	printed = p.Result()
+
+	buf.Reset()
+	wp.WithWidth(0)
+	ww.Walk(val)
+	assert.Nil(t, bw.Flush())
+	assert.Equal(t, printed, buf.String())
+
+	var (
+		sw  = &printerTestWriter{limit: -1}
+		sva = NewValueVisitorAdapter(NewValueWriterPrinter(sw).WithIndent("  "))
+		sv  = reflect.ValueOf([]int{1, 2})
+	)
+
+	sva.Init()
+	sva.VisitPreSlice(2, sv)
+	sva.VisitPreSliceIndex(2, 0, sv.Index(0))
+	sva.VisitInt(1)
+	sva.VisitPostSliceIndex(2, 0, sv.Index(0))
+	assert.Equal(t, "[]int{\n  1", strings.Join(sw.writes, ""))
+
+	sva.VisitPreSliceIndex(2, 1, sv.Index(1))
+	sva.VisitInt(2)
+	sva.VisitPostSliceIndex(2, 1, sv.Index(1))
+	sva.VisitPostSlice(2, sv)
+	assert.Equal(t, "[]int{\n  1,\n  2,\n}", strings.Join(sw.writes, ""))
+
+	// The column of a top level composite includes the text streamed before it
+	buf.Reset()
+	wp.WithWidth(11)
+	ww.Walk(&[]int{1, 2})
+	ww.Walk(&[]int{1})
+	assert.Nil(t, bw.Flush())
+	assert.Equal(t, "&[]int{\n  1,\n  2,\n}&[]int{1}", buf.String())
+
+	// The first write error is reported, and discards the rest of the output
+	tw = &printerTestWriter{limit: 2}
+	wp = NewValueWriterPrinter(tw)
+	ww.WithVisitor(NewValueVisitorAdapter(wp))
+	ww.Walk(val)
+	assert.Equal(t, fmt.Errorf("write limit reached"), wp.Err())
+	assert.Equal(t, []string{"map[string][]goreflect.walkerTestItem", "{"}, tw.writes)
+
+	// Each walk has its own error
+	tw.limit = -1
+	ww.Walk(1)
+	assert.Nil(t, wp.Err())
+
+	// Scalar printer
+	tw = &printerTestWriter{limit: -1}
+	sp := NewValueScalarWriterPrinter(tw)
+	sp.Init()
+	sp.QuoteStrings = true
+	sp.VisitString("a")
+	sp.VisitInt(1)
+	assert.Nil(t, sp.Err())
+	assert.Equal(t, "", sp.Result())
+	assert.Equal(t, []string{`"a"`, "1"}, tw.writes)
+	assert.Nil(t, NewValueScalarPrinter().Err())
+}
+
+type printerTestSecret struct {
+	Key string
+}
+
+func (s printerTestSecret) String() string {
+	return s.Key
+}
+
+type printerTestLogin struct {
+	User     string
+	Password string
+}
+
+func (l printerTestLogin) String() string {
+	return l.User + ":" + l.Password
+}
+
+func TestValuePrinterRedaction(t *testing.T) {
+	var (
+		p = NewValuePrinter().
+			WithQuotedStrings().
+			WithRedactTag("reflect").
+			WithRedactedFields(regexp.MustCompile("(?i)password")).
+			WithRedactedMapKeys(regexp.MustCompile("^token$")).
+			WithRedactedTypes(NewTypeMatch(printerTestSecret{}, 0, 1))
+		w     = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+		token = "t0k3n"
+		val   = struct {
+			User     string
+			Password string
+			PIN      int         `reflect:"name,redact"`
+			Keys     []string    `reflect:",redact"`
+			Token    *string     `reflect:",redact"`
+			Any      interface{} `reflect:",redact"`
+			Headers  map[string]interface{}
+			Secret   printerTestSecret
+			Secrets  []*printerTestSecret
+		}{
+			User:     "jane",
+			Password: "hunter2hunter2",
+			PIN:      1234,
+			Keys:     []string{"a", "b"},
+			Token:    &token,
+			Headers:  map[string]interface{}{"accept": "json", "token": []byte("abc")},
+			Secret:   printerTestSecret{Key: "k"},
+			Secrets:  []*printerTestSecret{{Key: "k"}, nil},
+		}
+	)
+
+	w.WithSortedMapKeys()
+	w.Walk(val)
+	assert.Equal(
+		t,
+		"struct { User string; Password string; PIN int \"reflect:\\\"name,redact\\\"\"; Keys []string \"reflect:\\\",redact\\\"\"; Token *string \"reflect:\\\",redact\\\"\"; Any interface {} \"reflect:\\\",redact\\\"\"; Headers map[string]interface {}; Secret goreflect.printerTestSecret; Secrets []*goreflect.printerTestSecret }{"+
+			`User: "jane", `+
+			"Password: <redacted len=14>, "+
+			"PIN: <redacted>, "+
+			"Keys: <redacted len=2>, "+
+			"Token: <redacted len=5>, "+
+			"Any: <redacted>, "+
+			`Headers: map[string]interface {}{"accept": "json", "token": <redacted len=3>}, `+
+			"Secret: <redacted>, "+
+			"Secrets: []*goreflect.printerTestSecret{<redacted>, <redacted>}}",
+		p.Result(),
+	)
+
+	// Redaction takes precedence over methods, formatters, Go syntax, and bytes modes
+	p.WithStringers().
+		WithFormatter("", func(v reflect.Value) string { return v.String() }).
+		WithGoSyntax().
+		WithBytesMode(BytesAsString)
+	w.Walk(val)
+	assert.NotContains(t, p.Result(), "hunter2")
+	assert.NotContains(t, p.Result(), "1234")
+	assert.NotContains(t, p.Result(), "t0k3n")
+	assert.NotContains(t, p.Result(), "abc")
+	assert.NotContains(t, p.Result(), `"k"`)
+	assert.Contains(t, p.Result(), "User: jane")
+
+	// The same tag can rename and redact fields, as the name is not an option
+	p = NewValuePrinter().WithRedactTag("reflect")
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	w.WithStructTag("reflect")
+	w.Walk(struct {
+		Login    string `reflect:"user"`
+		Password string `reflect:"pass,redact"`
+		PIN      int    `reflect:",redact"`
+		Redact   string `reflect:"redact"`
+	}{Login: "jane", Password: "hunter2", PIN: 1234, Redact: "shown"})
+	assert.Equal(
+		t,
+		"struct { Login string \"reflect:\\\"user\\\"\"; Password string \"reflect:\\\"pass,redact\\\"\"; PIN int \"reflect:\\\",redact\\\"\"; Redact string \"reflect:\\\"redact\\\"\" }{"+
+			"user: jane, "+
+			"pass: <redacted len=7>, "+
+			"PIN: <redacted>, "+
+			"redact: shown}",
+		p.Result(),
+	)
+
+	// A value with redacted components is walked rather than formatted, even if they are nested
+	p = NewValuePrinter().WithStringers().WithRedactedFields(regexp.MustCompile("(?i)password"))
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	login := printerTestLogin{User: "jane", Password: "hunter2"}
+	w.Walk([]interface{}{login, &struct{ Login printerTestLogin }{login}, printerTestSecret{Key: "k"}})
+	assert.Equal(
+		t,
+		"[]interface {}{"+
+			"goreflect.printerTestLogin{User: jane, Password: <redacted len=7>}, "+
+			"&struct { Login goreflect.printerTestLogin }{Login: goreflect.printerTestLogin{User: jane, Password: <redacted len=7>}}, "+
+			"k}",
+		p.Result(),
+	)
+
+	// Only the values of string keys are redacted, and only the component that is redacted is affected
+	p = NewValuePrinter().WithRedactedMapKeys(regexp.MustCompile("1"))
+	w = NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	w.WithSortedMapKeys()
+	w.Walk([]interface{}{map[int]int{1: 2}, map[string]int{"1": 2, "3": 4}, 5})
+	assert.Equal(t, "[]interface {}{map[int]int{1: 2}, map[string]int{1: <redacted>, 3: 4}, 5}", p.Result())
+
+	// Keys of an interface type that contain strings, and keys of named string types, are redacted
+	w.Walk(map[interface{}]interface{}{"1": "secret", 1: 2, nil: 3})
+	assert.Equal(t, "map[interface {}]interface {}{1: <redacted len=6>, 1: 2, nil: 3}", p.Result())
+
+	w.Walk(map[transformerTestName]string{"1": "secret", "2": "shown"})
+	assert.Equal(t, "map[goreflect.transformerTestName]string{1: <redacted len=6>, 2: shown}", p.Result())
 }
@@ -0,0 +1,552 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ValueEvent is a single visit recorded by a ValueRecorder.
+// It only contains strings, numbers, bools, and slices and pointers of other event types, so that a log of events can
+// be serialized (eg, with encoding/json or encoding/gob), and replayed later by Replay.
+type ValueEvent struct {
+	Method string                  // name of the ValueVisitor method, eg VisitInt
+	Path   []ValueEventPathElement // path of the value visited
+	Args   []ValueEventArg         // arguments of the method
+}
+
+// ValueEventArg is a recorded argument of a visit.
+// Only the members relevant to the argument are set:
+// - a reflect.StructField: Field
+// - a ValuePath: Path
+// - the invalid reflect.Value: Kind
+// - any other value: Kind, Type, and Text for bool, numeric, and string kinds, Nil for kinds that can be nil,
+// and Len for arrays, slices, and maps
+type ValueEventArg struct {
+	Kind  string                  // reflect.Kind of the value, eg int
+	Type  string                  // type of the value, eg []int
+	Text  string                  // text of a bool, number, or string
+	Nil   bool                    // true if the value is nil
+	Len   int                     // length of an array, slice, or map
+	Field *ValueEventField        // struct field
+	Path  []ValueEventPathElement // value path
+}
+
+// ValueEventField is a recorded reflect.StructField
+type ValueEventField struct {
+	Name      string
+	PkgPath   string
+	Type      string
+	Tag       string
+	Index     []int
+	Anonymous bool
+}
+
+// ValueEventPathElement is a recorded ValuePathElement
+type ValueEventPathElement struct {
+	Kind  ValuePathElementKind
+	Index int
+	Key   *ValueEventArg
+	Name  string
+}
+
+// ValueRecorder is a ValueVisitor that records a walk as a log of ValueEvents, one for each visit.
+// It is built on ValueVisitorProxy, and is PathAware so that the path of each visit is recorded.
+// The events can be passed to Replay to feed the same visits to any visitor, without the value that was walked.
+//
+// Values are recorded by their kind and type name, and bool, numeric, and string values by their text, so the
+// components of an array, slice, map, or struct are only recorded by their own visits.
+type ValueRecorder struct {
+	*ValueVisitorProxy
+	path   *ValuePath
+	events []ValueEvent
+}
+
+var (
+	reflectValueType = reflect.TypeOf(reflect.Value{})
+	structFieldType  = reflect.TypeOf(reflect.StructField{})
+	valuePathType    = reflect.TypeOf(ValuePath{})
+)
+
+// NewValueRecorder constructs a ValueRecorder with no events
+func NewValueRecorder() *ValueRecorder {
+	r := &ValueRecorder{}
+	r.ValueVisitorProxy = NewValueVisitorProxy(r.record)
+
+	return r
+}
+
+// Init discards the events of any previous walk
+func (r *ValueRecorder) Init() {
+	r.events = nil
+}
+
+// InitPath receives the path of each value visited
+func (r *ValueRecorder) InitPath(path *ValuePath) {
+	r.path = path
+}
+
+// Events returns the events recorded by the last walk
+func (r *ValueRecorder) Events() []ValueEvent {
+	return r.events
+}
+
+// record records a visit dispatched by the proxy
+func (r *ValueRecorder) record(method string, args []reflect.Value) {
+	event := ValueEvent{Method: method, Args: make([]ValueEventArg, len(args))}
+	if r.path != nil {
+		event.Path = recordPath(*r.path)
+	}
+
+	for i, arg := range args {
+		event.Args[i] = recordArg(arg)
+	}
+
+	r.events = append(r.events, event)
+}
+
+// recordPath returns the recorded elements of a path
+func recordPath(path ValuePath) []ValueEventPathElement {
+	elements := make([]ValueEventPathElement, path.Len())
+	for i, e := range path.elements {
+		elements[i] = ValueEventPathElement{Kind: e.Kind, Index: e.Index, Name: e.Name}
+		if e.Kind == PathMapKey || e.Kind == PathMapValue {
+			key := recordArg(e.Key)
+			elements[i].Key = &key
+		}
+	}
+
+	return elements
+}
+
+// recordArg returns a recorded argument.
+// A reflect.Value wrapped in a reflect.Value is unwrapped.
+func recordArg(v reflect.Value) ValueEventArg {
+	if v.IsValid() {
+		switch v.Type() {
+		case reflectValueType:
+			return recordArg(v.Interface().(reflect.Value))
+
+		case structFieldType:
+			f := v.Interface().(reflect.StructField)
+			return ValueEventArg{
+				Field: &ValueEventField{
+					Name:      f.Name,
+					PkgPath:   f.PkgPath,
+					Type:      f.Type.String(),
+					Tag:       string(f.Tag),
+					Index:     f.Index,
+					Anonymous: f.Anonymous,
+				},
+			}
+
+		case valuePathType:
+			return ValueEventArg{Path: recordPath(v.Interface().(ValuePath))}
+		}
+	}
+
+	arg := ValueEventArg{Kind: v.Kind().String()}
+	if !v.IsValid() {
+		return arg
+	}
+
+	arg.Type = v.Type().String()
+
+	switch v.Kind() {
+	case reflect.Bool:
+		arg.Text = strconv.FormatBool(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		arg.Text = strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		arg.Text = strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		arg.Text = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+
+	case reflect.Complex64, reflect.Complex128:
+		arg.Text = fmt.Sprint(v.Complex())
+
+	case reflect.String:
+		arg.Text = v.String()
+
+	case reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		arg.Nil = v.IsNil()
+
+	case reflect.Slice, reflect.Map:
+		arg.Nil = v.IsNil()
+		arg.Len = v.Len()
+
+	case reflect.Array:
+		arg.Len = v.Len()
+	}
+
+	return arg
+}
+
+// replayPreMethods and replayPostMethods are the pre and post methods of values with components
+var (
+	replayPreMethods = map[string]bool{
+		"VisitPrePtr":       true,
+		"VisitPreInterface": true,
+		"VisitPreArray":     true,
+		"VisitPreSlice":     true,
+		"VisitPreMap":       true,
+		"VisitPreStruct":    true,
+	}
+
+	replayPostMethods = map[string]bool{
+		"VisitPostPtr":       true,
+		"VisitPostInterface": true,
+		"VisitPostArray":     true,
+		"VisitPostSlice":     true,
+		"VisitPostMap":       true,
+		"VisitPostStruct":    true,
+	}
+
+	// replayMethods are the visit methods of ValueVisitor, which are all methods except Init
+	replayMethods = newReplayMethods()
+)
+
+// newReplayMethods returns the names of the visit methods of ValueVisitor
+func newReplayMethods() map[string]bool {
+	var (
+		methods = map[string]bool{}
+		typ     = reflect.TypeOf((*ValueVisitor)(nil)).Elem()
+	)
+
+	for i, n := 0, typ.NumMethod(); i < n; i++ {
+		if name := typ.Method(i).Name; name != "Init" {
+			methods[name] = true
+		}
+	}
+
+	return methods
+}
+
+// replayState is the state of a single replay
+type replayState struct {
+	types replayTypes
+	index int
+}
+
+// Replay feeds the given events to the given visitor, in the same way a walker would have when they were recorded.
+// The visitor can be any subset of ValueVisitor (see ValueVisitorAdapter), and is initialized before the first event.
+// If the visitor is PathAware, the path is that of each event as it is replayed.
+// If the visitor is a ValueWalkController, its actions are obeyed: the events inside a value whose components are
+// skipped are not replayed, and a stop ends the replay.
+//
+// Recorded values are replayed as new values of their types, with the same text for bool, numeric, and string kinds,
+// and nil or non-nil as recorded. Arrays and slices have the recorded length of zero elements, and maps are empty.
+// The types are looked up by name, from the given types and all types they contain, which can be values, reflect.Values,
+// or reflect.Types. Unknown types of bool, numeric, or string kinds are replayed as the basic type of the kind
+// (eg, an unknown Celsius int is replayed as an int), and any other unknown type is replayed as the invalid reflect.Value.
+//
+// A struct field is replayed with its recorded type, so the type of every recorded struct field must be given.
+//
+// Replay panics if an event has a method that is not a visit method of ValueVisitor, arguments that do not match the method,
+// or a struct field of an unknown type.
+func Replay(events []ValueEvent, visitor interface{}, types ...interface{}) {
+	var (
+		va     = NewValueVisitorAdapter(visitor)
+		vav    = reflect.ValueOf(va)
+		st     = &replayState{types: newReplayTypes(types)}
+		path   ValuePath
+		depth  int
+		skipAt int
+	)
+
+	va.Init()
+	va.InitPath(&path)
+
+	for i, event := range events {
+		st.index = i
+
+		isPre, isPost := replayPreMethods[event.Method], replayPostMethods[event.Method]
+		if skipAt > 0 {
+			switch {
+			case isPre:
+				depth++
+				continue
+
+			case isPost && (depth > skipAt):
+				depth--
+				continue
+
+			case isPost:
+				skipAt = 0
+
+			default:
+				continue
+			}
+		}
+
+		methodName := event.Method
+		if isPre {
+			methodName += "Action"
+		}
+
+		method := vav.MethodByName(methodName)
+		if !replayMethods[event.Method] || !method.IsValid() {
+			panic(fmt.Errorf("goreflect.Replay: event %d has method %s, which is not a ValueVisitor method", i, event.Method))
+		}
+
+		if method.Type().NumIn() != len(event.Args) {
+			panic(fmt.Errorf("goreflect.Replay: event %d has %d arguments, but %s accepts %d", i, len(event.Args), event.Method, method.Type().NumIn()))
+		}
+
+		args := make([]reflect.Value, len(event.Args))
+		for j := range event.Args {
+			args[j] = st.arg(event.Args[j], method.Type().In(j))
+		}
+
+		path.elements = st.path(event.Path)
+		results := method.Call(args)
+
+		switch {
+		case isPre:
+			depth++
+			switch results[0].Interface().(WalkAction) {
+			case SkipChildren:
+				skipAt = depth
+
+			case Stop:
+				return
+			}
+
+		case isPost:
+			depth--
+		}
+	}
+}
+
+// replayTypes is a type visitor that collects the types it visits by name
+type replayTypes map[string]reflect.Type
+
+// newReplayTypes returns the types of the given values, and all types they contain, by name
+func newReplayTypes(vals []interface{}) replayTypes {
+	var (
+		types = replayTypes{}
+		w     = NewTypeDepthFirstWalker(NewTypeVisitorAdapter(types))
+	)
+
+	for _, val := range vals {
+		w.Walk(val)
+	}
+
+	return types
+}
+
+// VisitScalarType collects a scalar type
+func (rt replayTypes) VisitScalarType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitInterfaceType collects an interface type
+func (rt replayTypes) VisitInterfaceType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPrePtrType collects a pointer type
+func (rt replayTypes) VisitPrePtrType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreArrayType collects an array type
+func (rt replayTypes) VisitPreArrayType(_ int, t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreSliceType collects a slice type
+func (rt replayTypes) VisitPreSliceType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreMapType collects a map type
+func (rt replayTypes) VisitPreMapType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreChanType collects a chan type
+func (rt replayTypes) VisitPreChanType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreStructType collects a struct type
+func (rt replayTypes) VisitPreStructType(_ int, t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// VisitPreFuncType collects a func type
+func (rt replayTypes) VisitPreFuncType(t reflect.Type) {
+	rt[t.String()] = t
+}
+
+// typeOf returns the type of a recorded argument, or nil if the type is unknown
+func (st *replayState) typeOf(arg ValueEventArg) reflect.Type {
+	if t, known := st.types[arg.Type]; known {
+		return t
+	}
+
+	for k := reflect.Bool; k <= reflect.UnsafePointer; k++ {
+		if k.String() == arg.Kind {
+			if t, basic := basicTypes[k]; basic {
+				return t
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// basicTypes are the basic types of the bool, numeric, and string kinds
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:       reflect.TypeOf(false),
+	reflect.Int:        reflect.TypeOf(int(0)),
+	reflect.Int8:       reflect.TypeOf(int8(0)),
+	reflect.Int16:      reflect.TypeOf(int16(0)),
+	reflect.Int32:      reflect.TypeOf(int32(0)),
+	reflect.Int64:      reflect.TypeOf(int64(0)),
+	reflect.Uint:       reflect.TypeOf(uint(0)),
+	reflect.Uint8:      reflect.TypeOf(uint8(0)),
+	reflect.Uint16:     reflect.TypeOf(uint16(0)),
+	reflect.Uint32:     reflect.TypeOf(uint32(0)),
+	reflect.Uint64:     reflect.TypeOf(uint64(0)),
+	reflect.Uintptr:    reflect.TypeOf(uintptr(0)),
+	reflect.Float32:    reflect.TypeOf(float32(0)),
+	reflect.Float64:    reflect.TypeOf(float64(0)),
+	reflect.Complex64:  reflect.TypeOf(complex64(0)),
+	reflect.Complex128: reflect.TypeOf(complex128(0)),
+	reflect.String:     reflect.TypeOf(""),
+}
+
+// arg returns the argument of the given type for a recorded argument
+func (st *replayState) arg(arg ValueEventArg, typ reflect.Type) reflect.Value {
+	switch typ {
+	case reflectValueType:
+		return reflect.ValueOf(st.value(arg))
+
+	case structFieldType:
+		if arg.Field == nil {
+			panic(fmt.Errorf("goreflect.Replay: event %d has no struct field for an argument of type %s", st.index, typ))
+		}
+
+		fieldType, known := st.types[arg.Field.Type]
+		if !known {
+			panic(fmt.Errorf("goreflect.Replay: event %d has struct field %s of unknown type %s", st.index, arg.Field.Name, arg.Field.Type))
+		}
+
+		return reflect.ValueOf(reflect.StructField{
+			Name:      arg.Field.Name,
+			PkgPath:   arg.Field.PkgPath,
+			Type:      fieldType,
+			Tag:       reflect.StructTag(arg.Field.Tag),
+			Index:     arg.Field.Index,
+			Anonymous: arg.Field.Anonymous,
+		})
+
+	case valuePathType:
+		return reflect.ValueOf(ValuePath{elements: st.path(arg.Path)})
+	}
+
+	v := reflect.New(typ).Elem()
+	st.setText(v, arg.Text)
+
+	return v
+}
+
+// value returns a new value for a recorded argument
+func (st *replayState) value(arg ValueEventArg) reflect.Value {
+	typ := st.typeOf(arg)
+	if typ == nil {
+		return reflect.Value{}
+	}
+
+	v := reflect.New(typ).Elem()
+	if arg.Nil {
+		return v
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String:
+		st.setText(v, arg.Text)
+
+	case reflect.Ptr:
+		v = reflect.New(typ.Elem())
+
+	case reflect.Slice:
+		v = reflect.MakeSlice(typ, arg.Len, arg.Len)
+
+	case reflect.Map:
+		v = reflect.MakeMapWithSize(typ, arg.Len)
+
+	case reflect.Chan:
+		if typ.ChanDir() == reflect.BothDir {
+			v = reflect.MakeChan(typ, 0)
+		}
+	}
+
+	return v
+}
+
+// setText sets a bool, numeric, or string value from its recorded text
+func (st *replayState) setText(v reflect.Value, text string) {
+	var err error
+
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(text, 10, v.Type().Bits())
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, v.Type().Bits())
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, v.Type().Bits())
+		v.SetFloat(f)
+
+	case reflect.Complex64, reflect.Complex128:
+		var c complex128
+		_, err = fmt.Sscan(text, &c)
+		v.SetComplex(c)
+
+	case reflect.String:
+		v.SetString(text)
+
+	default:
+		panic(fmt.Errorf("goreflect.Replay: event %d has an argument of type %s, which cannot be replayed", st.index, v.Type()))
+	}
+
+	if err != nil {
+		panic(fmt.Errorf("goreflect.Replay: event %d has an invalid %s %q: %w", st.index, v.Type(), text, err))
+	}
+}
+
+// path returns the path elements of recorded path elements
+func (st *replayState) path(elements []ValueEventPathElement) []ValuePathElement {
+	path := make([]ValuePathElement, len(elements))
+	for i, e := range elements {
+		path[i] = ValuePathElement{Kind: e.Kind, Index: e.Index, Name: e.Name}
+		if e.Key != nil {
+			path[i].Key = st.value(*e.Key)
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,170 @@
+package goreflect
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueRecorder(t *testing.T) {
+	var (
+		r = NewValueRecorder()
+		w = NewValueDepthFirstWalker(r)
+	)
+
+	w.WithSortedMapKeys()
+	w.Walk(map[string][]int{"a": {1}})
+	assert.Equal(
+		t,
+		[]ValueEvent{
+			{Method: "VisitPreMap", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "map", Type: "map[string][]int", Len: 1}}},
+			{Method: "VisitPreMapKeyValue", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "string", Type: "string", Text: "a"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPreMapKey", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "string", Type: "string", Text: "a"}}},
+			{Method: "VisitString", Path: []ValueEventPathElement{{Kind: PathMapKey, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}}, Args: []ValueEventArg{{Kind: "string", Type: "string", Text: "a"}}},
+			{Method: "VisitPostMapKey", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "string", Type: "string", Text: "a"}}},
+			{Method: "VisitPreMapValue", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPreSlice", Path: []ValueEventPathElement{{Kind: PathMapValue, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPreSliceIndex", Path: []ValueEventPathElement{{Kind: PathMapValue, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "int", Type: "int", Text: "1"}}},
+			{Method: "VisitInt", Path: []ValueEventPathElement{{Kind: PathMapValue, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}, {Kind: PathIndex}}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}}},
+			{Method: "VisitPostSliceIndex", Path: []ValueEventPathElement{{Kind: PathMapValue, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "int", Type: "int", Text: "1"}}},
+			{Method: "VisitPostSlice", Path: []ValueEventPathElement{{Kind: PathMapValue, Key: &ValueEventArg{Kind: "string", Type: "string", Text: "a"}}}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPostMapValue", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPostMapKeyValue", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "int", Type: "int", Text: "0"}, {Kind: "string", Type: "string", Text: "a"}, {Kind: "slice", Type: "[]int", Len: 1}}},
+			{Method: "VisitPostMap", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "int", Type: "int", Text: "1"}, {Kind: "map", Type: "map[string][]int", Len: 1}}},
+		},
+		r.Events(),
+	)
+
+	// Each walk is recorded separately
+	w.Walk(nil)
+	assert.Equal(t, []ValueEvent{{Method: "VisitNil", Path: []ValueEventPathElement{}, Args: []ValueEventArg{{Kind: "invalid"}}}}, r.Events())
+}
+
+func TestReplay(t *testing.T) {
+	var (
+		r   = NewValueRecorder()
+		w   = NewValueDepthFirstWalker(r)
+		val = []*walkerTestItem{
+			{Name: "a", Price: new(int)},
+			nil,
+			{Name: ""},
+		}
+	)
+
+	// Record a walk, and serialize it
+	w.Walk(val)
+	serialized, err := json.Marshal(r.Events())
+	assert.Nil(t, err)
+
+	var events []ValueEvent
+	assert.Nil(t, json.Unmarshal(serialized, &events))
+
+	// Replay to a printer, which prints the same as walking the value, given the types of the value
+	p := NewValuePrinter()
+	NewValueDepthFirstWalker(NewValueVisitorAdapter(p)).Walk(val)
+	printed := p.Result()
+
+	Replay(events, p, val)
+	assert.Equal(t, printed, p.Result())
+
+	// Replay to a PathAware visitor
+	v := &walkerTestValidator{}
+	Replay(events, v, val)
+	assert.Equal(t, []string{"[1]", "[2].Name", "[2].Price"}, v.invalid)
+
+	// Replay obeys walk actions
+	c := &walkerTestController{}
+	w.Walk([]interface{}{[]int{1, 2, 3}, []int{4}})
+	Replay(r.Events(), c)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitPreSlice(1)",
+			"VisitInt(4)",
+			"VisitPostSlice(1)",
+			"VisitPostSlice(2)",
+		},
+		c.visits,
+	)
+
+	c.visits = nil
+	w.Walk([]interface{}{map[int]int{5: 6}, 7})
+	Replay(r.Events(), c)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreMapAction",
+		},
+		c.visits,
+	)
+
+	// Scalars of unknown types are replayed as their basic types
+	var (
+		replayed []string
+		d        = NewValueVisitorProxy(func(m string, a []reflect.Value) {
+			replayed = append(replayed, fmt.Sprintf("%s(%v)", m, a[len(a)-1]))
+		})
+	)
+
+	w.Walk([]filterTestCelsius{20})
+	Replay(r.Events(), d)
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(<invalid reflect.Value>)",
+			"VisitPreSliceIndex(20)",
+			"VisitInt(20)",
+			"VisitPostSliceIndex(20)",
+			"VisitPostSlice(<invalid reflect.Value>)",
+		},
+		replayed,
+	)
+
+	// Events that are not visits cannot be replayed
+	func() {
+		defer func() {
+			assert.Equal(t, fmt.Errorf("goreflect.Replay: event 0 has method Walk, which is not a ValueVisitor method"), recover())
+		}()
+
+		Replay([]ValueEvent{{Method: "Walk"}}, d)
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Methods of the adapter that are not visits cannot be replayed
+	for _, method := range []string{"Init", "WithVisitor", "InitPath"} {
+		func() {
+			defer func() {
+				assert.Equal(t, fmt.Errorf("goreflect.Replay: event 0 has method %s, which is not a ValueVisitor method", method), recover())
+			}()
+
+			Replay([]ValueEvent{{Method: method}}, d)
+			assert.Fail(t, "Must panic")
+		}()
+	}
+
+	func() {
+		defer func() {
+			assert.Equal(t, fmt.Errorf("goreflect.Replay: event 0 has 0 arguments, but VisitInt accepts 1"), recover())
+		}()
+
+		Replay([]ValueEvent{{Method: "VisitInt"}}, d)
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Struct fields cannot be replayed without their types
+	func() {
+		defer func() {
+			assert.Equal(t, fmt.Errorf("goreflect.Replay: event 4 has struct field Name of unknown type string"), recover())
+		}()
+
+		Replay(events, v)
+		assert.Fail(t, "Must panic")
+	}()
+}
@@ -0,0 +1,211 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValueTransformer transforms a value into a new value, by replacing values of chosen kinds using hooks.
+// The value is walked depth first, and rebuilt bottom up: the components of an array, slice, map, ptr, interface,
+// or struct are transformed first, then a new value is built from the transformed components, and that is passed to the
+// hook for its kind. The value given to Transform is never modified.
+//
+// A hook receives a value of its kind, and returns the replacement value, which must be assignable to the type of
+// the location the value is stored in (eg, the element type of a slice). A hook may return its argument to keep it as is.
+// The value returned by a hook is not transformed further.
+// Hooks are called for nil values too, so that they can be replaced (eg, a nil slice with an empty slice).
+// A nil interface{} given to Transform is passed to the hook for reflect.Invalid.
+// Values of kinds that have no hook are kept as is, except that their components are transformed.
+//
+// Only exported struct fields are transformed, as unexported fields cannot be set. Unexported fields are copied as is.
+// A ptr, slice, or map that is referred to more than once is transformed once, so that the result refers to the same
+// transformed value each time. A ptr, slice, or map that refers back to a value that contains it cannot be rebuilt
+// bottom up, and causes a panic.
+//
+// Example that trims all strings and replaces nil slices with empty slices:
+//
+//	NewValueTransformer().
+//		WithKindHook(reflect.String, func(v reflect.Value) reflect.Value {
+//			return reflect.ValueOf(strings.TrimSpace(v.String())).Convert(v.Type())
+//		}).
+//		WithKindHook(reflect.Slice, func(v reflect.Value) reflect.Value {
+//			if v.IsNil() {
+//				return reflect.MakeSlice(v.Type(), 0, 0)
+//			}
+//			return v
+//		}).
+//		Transform(val)
+type ValueTransformer struct {
+	hooks map[reflect.Kind]func(reflect.Value) reflect.Value
+}
+
+// valueTransformState is the state of a single transform
+type valueTransformState struct {
+	path        ValuePath
+	transformed map[valueRef]reflect.Value
+	inProgress  map[valueRef]ValuePath
+}
+
+// NewValueTransformer constructs a ValueTransformer with no hooks
+func NewValueTransformer() *ValueTransformer {
+	return &ValueTransformer{hooks: map[reflect.Kind]func(reflect.Value) reflect.Value{}}
+}
+
+// WithKindHook is a builder method that sets the hook for values of the given kind, replacing any previous hook
+func (t *ValueTransformer) WithKindHook(kind reflect.Kind, hook func(reflect.Value) reflect.Value) *ValueTransformer {
+	t.hooks[kind] = hook
+	return t
+}
+
+// Transform returns the transformed value.
+// The value passed can be a reflect.Value wrapper or a plain value.
+func (t ValueTransformer) Transform(val interface{}) reflect.Value {
+	var (
+		v  = GetReflectValueOf(val)
+		st = &valueTransformState{transformed: map[valueRef]reflect.Value{}, inProgress: map[valueRef]ValuePath{}}
+	)
+
+	if !v.IsValid() {
+		return t.hook(v, nil, st)
+	}
+
+	return t.transform(v, v.Type(), st)
+}
+
+// hook applies the hook for the kind of the given value, if there is one.
+// The result must be assignable to the given slot type, unless it is nil.
+func (t ValueTransformer) hook(v reflect.Value, slot reflect.Type, st *valueTransformState) reflect.Value {
+	hook, ok := t.hooks[v.Kind()]
+	if !ok {
+		return v
+	}
+
+	result := hook(v)
+	if (slot != nil) && (!result.IsValid() || !result.Type().AssignableTo(slot)) {
+		resultType := "an invalid value"
+		if result.IsValid() {
+			resultType = result.Type().String()
+		}
+
+		panic(fmt.Errorf("goreflect.ValueTransformer.Transform: %s hook at %q returned %s, which is not assignable to %s", v.Kind(), st.path, resultType, slot))
+	}
+
+	return result
+}
+
+// transform returns the transformed value of the given value, which must be assignable to the given slot type
+func (t ValueTransformer) transform(v reflect.Value, slot reflect.Type, st *valueTransformState) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		ref, trackable := refOf(v)
+		if !trackable {
+			return t.hook(v, slot, st)
+		}
+
+		if result, transformed := st.transformed[ref]; transformed {
+			return result
+		}
+
+		if path, inProgress := st.inProgress[ref]; inProgress {
+			panic(fmt.Errorf("goreflect.ValueTransformer.Transform: value of type %s at %q refers back to %q", v.Type(), st.path, path))
+		}
+
+		st.inProgress[ref] = st.path.Copy()
+		result := t.hook(t.rebuild(v, st), slot, st)
+		delete(st.inProgress, ref)
+		st.transformed[ref] = result
+
+		return result
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return t.hook(v, slot, st)
+		}
+
+		return t.hook(t.rebuild(v, st), slot, st)
+
+	case reflect.Array, reflect.Struct:
+		return t.hook(t.rebuild(v, st), slot, st)
+	}
+
+	return t.hook(v, slot, st)
+}
+
+// rebuild returns a new value of the same type as the given non-nil ptr, interface, array, slice, map, or struct,
+// whose components are the transformed components of the given value
+func (t ValueTransformer) rebuild(v reflect.Value, st *valueTransformState) reflect.Value {
+	typ := v.Type()
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		result := reflect.New(typ.Elem())
+
+		st.path.push(ValuePathElement{Kind: PathDeref})
+		result.Elem().Set(t.transform(v.Elem(), typ.Elem(), st))
+		st.path.pop()
+
+		return result
+
+	case reflect.Interface:
+		result := reflect.New(typ).Elem()
+
+		st.path.push(ValuePathElement{Kind: PathInterface})
+		result.Set(t.transform(v.Elem(), typ, st))
+		st.path.pop()
+
+		return result
+
+	case reflect.Array, reflect.Slice:
+		var result reflect.Value
+		if v.Kind() == reflect.Array {
+			result = reflect.New(typ).Elem()
+		} else {
+			result = reflect.MakeSlice(typ, v.Len(), v.Len())
+		}
+
+		for i, n := 0, v.Len(); i < n; i++ {
+			st.path.push(ValuePathElement{Kind: PathIndex, Index: i})
+			result.Index(i).Set(t.transform(v.Index(i), typ.Elem(), st))
+			st.path.pop()
+		}
+
+		return result
+
+	case reflect.Map:
+		result := reflect.MakeMapWithSize(typ, v.Len())
+
+		iter := v.MapRange()
+		for i := 0; iter.Next(); i++ {
+			mk, mv := iter.Key(), iter.Value()
+
+			st.path.push(ValuePathElement{Kind: PathMapKey, Index: i, Key: mk})
+			tk := t.transform(mk, typ.Key(), st)
+			st.path.pop()
+
+			st.path.push(ValuePathElement{Kind: PathMapValue, Index: i, Key: mk})
+			tv := t.transform(mv, typ.Elem(), st)
+			st.path.pop()
+
+			result.SetMapIndex(tk, tv)
+		}
+
+		return result
+	}
+
+	// Struct
+	result := reflect.New(typ).Elem()
+	result.Set(v)
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		st.path.push(ValuePathElement{Kind: PathField, Index: i, Name: sf.Name})
+		result.Field(i).Set(t.transform(v.Field(i), sf.Type, st))
+		st.path.pop()
+	}
+
+	return result
+}
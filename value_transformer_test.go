@@ -0,0 +1,121 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type transformerTestName string
+
+type transformerTestPerson struct {
+	Name     transformerTestName
+	Tags     []string
+	Parent   *transformerTestPerson
+	Extra    interface{}
+	internal string
+}
+
+func TestValueTransformer(t *testing.T) {
+	var (
+		trim = NewValueTransformer().
+			WithKindHook(reflect.String, func(v reflect.Value) reflect.Value {
+				return reflect.ValueOf(strings.TrimSpace(v.String())).Convert(v.Type())
+			}).
+			WithKindHook(reflect.Slice, func(v reflect.Value) reflect.Value {
+				if v.IsNil() {
+					return reflect.MakeSlice(v.Type(), 0, 0)
+				}
+				return v
+			})
+		parent = &transformerTestPerson{Name: " mom "}
+		person = transformerTestPerson{
+			Name:     " joe ",
+			Tags:     []string{" a", "b "},
+			Parent:   parent,
+			Extra:    map[string]interface{}{" k ": " v ", "p": parent},
+			internal: " x ",
+		}
+	)
+
+	// Strings are trimmed and nil slices are made empty throughout, the original is not modified
+	result := trim.Transform(person).Interface().(transformerTestPerson)
+	assert.Equal(t, transformerTestName("joe"), result.Name)
+	assert.Equal(t, []string{"a", "b"}, result.Tags)
+	assert.Equal(t, transformerTestName("mom"), result.Parent.Name)
+	assert.Equal(t, []string{}, result.Parent.Tags)
+	assert.Equal(t, " x ", result.internal)
+	assert.Equal(t, transformerTestName(" joe "), person.Name)
+	assert.Equal(t, transformerTestName(" mom "), parent.Name)
+	assert.Nil(t, parent.Tags)
+
+	// Values referred to more than once are transformed once
+	extra := result.Extra.(map[string]interface{})
+	assert.Equal(t, "v", extra["k"])
+	assert.True(t, extra["p"].(*transformerTestPerson) == result.Parent)
+
+	// Reflect value wrappers, scalars, and nil
+	assert.Equal(t, "a", trim.Transform(reflect.ValueOf(" a ")).Interface())
+	assert.Equal(t, 5, trim.Transform(5).Interface())
+	assert.False(t, trim.Transform(nil).IsValid())
+
+	// Hooks can replace nil pointers and nil interfaces, and their results are not transformed further
+	defaults := NewValueTransformer().
+		WithKindHook(reflect.Ptr, func(v reflect.Value) reflect.Value {
+			if v.IsNil() {
+				return reflect.New(v.Type().Elem())
+			}
+			return v
+		}).
+		WithKindHook(reflect.Interface, func(v reflect.Value) reflect.Value {
+			if v.IsNil() {
+				return reflect.ValueOf("none")
+			}
+			return v
+		})
+	result = defaults.Transform(transformerTestPerson{}).Interface().(transformerTestPerson)
+	assert.Equal(t, &transformerTestPerson{}, result.Parent)
+	assert.Equal(t, "none", result.Extra)
+
+	// Arrays, and interfaces that contain values of a different kind
+	doubler := NewValueTransformer().WithKindHook(reflect.Int, func(v reflect.Value) reflect.Value {
+		return reflect.ValueOf(v.Int() * 2).Convert(v.Type())
+	})
+	assert.Equal(t, [2]int{2, 4}, doubler.Transform([2]int{1, 2}).Interface())
+	assert.Equal(t, []interface{}{2, "a"}, doubler.Transform([]interface{}{1, "a"}).Interface())
+
+	// Hooks that return a value that cannot be stored
+	func() {
+		defer func() {
+			assert.Equal(
+				t,
+				fmt.Errorf(`goreflect.ValueTransformer.Transform: int hook at "[0]" returned string, which is not assignable to int`),
+				recover(),
+			)
+		}()
+
+		NewValueTransformer().WithKindHook(reflect.Int, func(reflect.Value) reflect.Value {
+			return reflect.ValueOf("a")
+		}).Transform([]int{1})
+		assert.Fail(t, "Must panic")
+	}()
+
+	// Cycles
+	func() {
+		defer func() {
+			assert.Equal(
+				t,
+				fmt.Errorf(`goreflect.ValueTransformer.Transform: value of type *goreflect.transformerTestPerson at ".Parent" refers back to ""`),
+				recover(),
+			)
+		}()
+
+		cycle := &transformerTestPerson{}
+		cycle.Parent = cycle
+		trim.Transform(cycle)
+		assert.Fail(t, "Must panic")
+	}()
+}
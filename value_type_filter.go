@@ -0,0 +1,495 @@
+package goreflect
+
+import (
+	"context"
+	"reflect"
+)
+
+// ValueTypeFilter is a ValueVisitor that forwards to another visitor only the values whose type matches,
+// such as all time.Time or all *Money values in an arbitrary structure.
+// The visitor can be any subset of ValueVisitor, as it is adapted by a ValueVisitorAdapter.
+//
+// When a value matches, the visitor receives all visits for the value and the values it contains (eg, the pre visit,
+// fields, and post visit of a struct), as if the matching value had been walked on its own. The visitor receives no
+// visits for values that do not match and are not inside a matching value, and the components of such values are
+// searched for matching values. A matching value inside a matching value is not matched again, it is just part of the
+// enclosing matching value.
+//
+// The type of a value is its type as stored in its container, so a named type such as type Celsius int is matched as
+// Celsius, and a value inside an interface is matched as its dynamic type. A scalar value given to the walk is matched
+// by its basic type (eg, a top level Celsius is matched as int), as the scalar visit methods do not receive the type.
+// A nil interface{} given to the walk has no type, and never matches.
+//
+// ValueTypeFilter is PathAware, ContextAware, and a ValueWalkController, and passes the path, context, and actions of
+// the visitor if it is PathAware, ContextAware, or a ValueWalkController. The actions of the visitor only apply inside
+// matching values.
+type ValueTypeFilter struct {
+	visitor   *ValueVisitorAdapter
+	matches   func(reflect.Type) bool
+	pending   reflect.Type
+	depth     int
+	matchedAt int
+}
+
+// NewValueTypeFilter constructs a ValueTypeFilter that forwards the values whose type satisfies the given TypeMatch,
+// including the number of indirections (eg, NewTypeMatch(Money{}, 1) matches *Money values)
+func NewValueTypeFilter(match TypeMatch, visitor interface{}) *ValueTypeFilter {
+	return &ValueTypeFilter{
+		visitor: NewValueVisitorAdapter(visitor),
+		matches: match.Matches,
+	}
+}
+
+// NewValueFuncFilter constructs a ValueTypeFilter that forwards the func values whose type satisfies the given FuncMatcher
+func NewValueFuncFilter(matcher *FuncMatcher, visitor interface{}) *ValueTypeFilter {
+	return &ValueTypeFilter{
+		visitor: NewValueVisitorAdapter(visitor),
+		matches: func(t reflect.Type) bool {
+			return (t != nil) && matcher.Matches(t)
+		},
+	}
+}
+
+// typeOfValue returns the type of the given value, or nil if it is the invalid value
+func typeOfValue(v reflect.Value) reflect.Type {
+	if !v.IsValid() {
+		return nil
+	}
+
+	return v.Type()
+}
+
+// scalarType returns the type of the scalar value being visited, which is the type recorded by the last component
+// visit, or the type of the given basic value for a top level value
+func (vf *ValueTypeFilter) scalarType(v interface{}) reflect.Type {
+	if vf.pending != nil {
+		return vf.pending
+	}
+
+	return reflect.TypeOf(v)
+}
+
+// forward calls the given function with the visitor, if a matching value is being visited
+func (vf *ValueTypeFilter) forward(visit func(va *ValueVisitorAdapter)) {
+	if vf.matchedAt > 0 {
+		visit(vf.visitor)
+	}
+}
+
+// visit calls the given function with the visitor for a value that has no components, if it is of the given type
+// that matches, or a matching value is being visited
+func (vf *ValueTypeFilter) visit(typ reflect.Type, visit func(va *ValueVisitorAdapter)) {
+	vf.pending = nil
+
+	if (vf.matchedAt > 0) || vf.matches(typ) {
+		visit(vf.visitor)
+	}
+}
+
+// component forwards a visit for a component of an array, slice, map, or struct, and records the type of the component
+func (vf *ValueTypeFilter) component(v reflect.Value, visit func(va *ValueVisitorAdapter)) {
+	vf.forward(visit)
+	vf.pending = v.Type()
+}
+
+// pre begins a value that has components, which becomes the matching value if it matches.
+// If a matching value is being visited, the given action function is called with the visitor, and its result is returned.
+// Otherwise, Continue is returned.
+// The type of the value a ptr points to or an interface contains is recorded for when it is visited.
+func (vf *ValueTypeFilter) pre(v reflect.Value, action func(va *ValueVisitorAdapter) WalkAction) WalkAction {
+	vf.pending = nil
+	if k := v.Kind(); (k == reflect.Ptr) || (k == reflect.Interface) {
+		vf.pending = v.Elem().Type()
+	}
+
+	vf.depth++
+	if (vf.matchedAt == 0) && vf.matches(v.Type()) {
+		vf.matchedAt = vf.depth
+	}
+
+	if vf.matchedAt > 0 {
+		return action(vf.visitor)
+	}
+
+	return Continue
+}
+
+// post ends a value that has components, calling the given function with the visitor if a matching value is being
+// visited. If the value is the matching value, then no matching value is being visited afterwards.
+func (vf *ValueTypeFilter) post(visit func(va *ValueVisitorAdapter)) {
+	vf.pending = nil
+	vf.forward(visit)
+
+	if vf.matchedAt == vf.depth {
+		vf.matchedAt = 0
+	}
+
+	vf.depth--
+}
+
+// Init resets the state of the filter, and forwards to the visitor
+func (vf *ValueTypeFilter) Init() {
+	vf.pending, vf.depth, vf.matchedAt = nil, 0, 0
+	vf.visitor.Init()
+}
+
+// InitPath forwards to the visitor
+func (vf *ValueTypeFilter) InitPath(path *ValuePath) {
+	vf.visitor.InitPath(path)
+}
+
+// InitContext forwards to the visitor
+func (vf *ValueTypeFilter) InitContext(ctx context.Context) {
+	vf.visitor.InitContext(ctx)
+}
+
+// VisitBool forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitBool(v bool) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitBool(v)
+	})
+}
+
+// VisitInt forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitInt(v int) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitInt(v)
+	})
+}
+
+// VisitInt8 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitInt8(v int8) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitInt8(v)
+	})
+}
+
+// VisitInt16 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitInt16(v int16) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitInt16(v)
+	})
+}
+
+// VisitInt32 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitInt32(v int32) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitInt32(v)
+	})
+}
+
+// VisitInt64 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitInt64(v int64) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitInt64(v)
+	})
+}
+
+// VisitUint forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitUint(v uint) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitUint(v)
+	})
+}
+
+// VisitUint8 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitUint8(v uint8) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitUint8(v)
+	})
+}
+
+// VisitUint16 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitUint16(v uint16) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitUint16(v)
+	})
+}
+
+// VisitUint32 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitUint32(v uint32) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitUint32(v)
+	})
+}
+
+// VisitUint64 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitUint64(v uint64) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitUint64(v)
+	})
+}
+
+// VisitFloat32 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitFloat32(v float32) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitFloat32(v)
+	})
+}
+
+// VisitFloat64 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitFloat64(v float64) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitFloat64(v)
+	})
+}
+
+// VisitComplex64 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitComplex64(v complex64) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitComplex64(v)
+	})
+}
+
+// VisitComplex128 forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitComplex128(v complex128) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitComplex128(v)
+	})
+}
+
+// VisitString forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitString(v string) {
+	vf.visit(vf.scalarType(v), func(va *ValueVisitorAdapter) {
+		va.VisitString(v)
+	})
+}
+
+// VisitChan forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitChan(v reflect.Value) {
+	vf.visit(typeOfValue(v), func(va *ValueVisitorAdapter) {
+		va.VisitChan(v)
+	})
+}
+
+// VisitFunc forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitFunc(v reflect.Value) {
+	vf.visit(typeOfValue(v), func(va *ValueVisitorAdapter) {
+		va.VisitFunc(v)
+	})
+}
+
+// VisitNil forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitNil(v reflect.Value) {
+	vf.visit(typeOfValue(v), func(va *ValueVisitorAdapter) {
+		va.VisitNil(v)
+	})
+}
+
+// VisitPrePtr is the same as VisitPrePtrAction
+func (vf *ValueTypeFilter) VisitPrePtr(v reflect.Value) {
+	vf.VisitPrePtrAction(v)
+}
+
+// VisitPostPtr forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostPtr(v reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostPtr(v)
+	})
+}
+
+// VisitPreInterface is the same as VisitPreInterfaceAction
+func (vf *ValueTypeFilter) VisitPreInterface(v reflect.Value) {
+	vf.VisitPreInterfaceAction(v)
+}
+
+// VisitPostInterface forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostInterface(v reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostInterface(v)
+	})
+}
+
+// VisitPreArray is the same as VisitPreArrayAction
+func (vf *ValueTypeFilter) VisitPreArray(length int, v reflect.Value) {
+	vf.VisitPreArrayAction(length, v)
+}
+
+// VisitPreArrayIndex forwards if the array is in a matching value, and records the type of the element to visit
+func (vf *ValueTypeFilter) VisitPreArrayIndex(length int, index int, v reflect.Value) {
+	vf.component(v, func(va *ValueVisitorAdapter) {
+		va.VisitPreArrayIndex(length, index, v)
+	})
+}
+
+// VisitPostArrayIndex forwards if the array is in a matching value
+func (vf *ValueTypeFilter) VisitPostArrayIndex(length int, index int, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostArrayIndex(length, index, v)
+	})
+}
+
+// VisitPostArray forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostArray(length int, v reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostArray(length, v)
+	})
+}
+
+// VisitPreSlice is the same as VisitPreSliceAction
+func (vf *ValueTypeFilter) VisitPreSlice(length int, v reflect.Value) {
+	vf.VisitPreSliceAction(length, v)
+}
+
+// VisitPreSliceIndex forwards if the slice is in a matching value, and records the type of the element to visit
+func (vf *ValueTypeFilter) VisitPreSliceIndex(length int, index int, v reflect.Value) {
+	vf.component(v, func(va *ValueVisitorAdapter) {
+		va.VisitPreSliceIndex(length, index, v)
+	})
+}
+
+// VisitPostSliceIndex forwards if the slice is in a matching value
+func (vf *ValueTypeFilter) VisitPostSliceIndex(length int, index int, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostSliceIndex(length, index, v)
+	})
+}
+
+// VisitPostSlice forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostSlice(length int, v reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostSlice(length, v)
+	})
+}
+
+// VisitPreMap is the same as VisitPreMapAction
+func (vf *ValueTypeFilter) VisitPreMap(length int, m reflect.Value) {
+	vf.VisitPreMapAction(length, m)
+}
+
+// VisitPreMapKeyValue forwards if the map is in a matching value
+func (vf *ValueTypeFilter) VisitPreMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreMapKeyValue(length, index, k, v)
+	})
+}
+
+// VisitPreMapKey forwards if the map is in a matching value, and records the type of the key to visit
+func (vf *ValueTypeFilter) VisitPreMapKey(length int, index int, k reflect.Value) {
+	vf.component(k, func(va *ValueVisitorAdapter) {
+		va.VisitPreMapKey(length, index, k)
+	})
+}
+
+// VisitPostMapKey forwards if the map is in a matching value
+func (vf *ValueTypeFilter) VisitPostMapKey(length int, index int, k reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapKey(length, index, k)
+	})
+}
+
+// VisitPreMapValue forwards if the map is in a matching value, and records the type of the value to visit
+func (vf *ValueTypeFilter) VisitPreMapValue(length int, index int, v reflect.Value) {
+	vf.component(v, func(va *ValueVisitorAdapter) {
+		va.VisitPreMapValue(length, index, v)
+	})
+}
+
+// VisitPostMapValue forwards if the map is in a matching value
+func (vf *ValueTypeFilter) VisitPostMapValue(length int, index int, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapValue(length, index, v)
+	})
+}
+
+// VisitPostMapKeyValue forwards if the map is in a matching value
+func (vf *ValueTypeFilter) VisitPostMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapKeyValue(length, index, k, v)
+	})
+}
+
+// VisitPostMap forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostMap(length int, m reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostMap(length, m)
+	})
+}
+
+// VisitPreStruct is the same as VisitPreStructAction
+func (vf *ValueTypeFilter) VisitPreStruct(length int, v reflect.Value) {
+	vf.VisitPreStructAction(length, v)
+}
+
+// VisitPreStructFieldValue forwards if the struct is in a matching value, and records the type of the field value to visit
+func (vf *ValueTypeFilter) VisitPreStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) {
+	vf.component(v, func(va *ValueVisitorAdapter) {
+		va.VisitPreStructFieldValue(length, index, f, v)
+	})
+}
+
+// VisitPostStructFieldValue forwards if the struct is in a matching value
+func (vf *ValueTypeFilter) VisitPostStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostStructFieldValue(length, index, f, v)
+	})
+}
+
+// VisitPostStruct forwards if the value is in a matching value, and ends a matching value
+func (vf *ValueTypeFilter) VisitPostStruct(length int, v reflect.Value) {
+	vf.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostStruct(length, v)
+	})
+}
+
+// VisitBackReference forwards if the value matches or is in a matching value
+func (vf *ValueTypeFilter) VisitBackReference(v reflect.Value, path ValuePath) {
+	vf.visit(typeOfValue(v), func(va *ValueVisitorAdapter) {
+		va.VisitBackReference(v, path)
+	})
+}
+
+// VisitTruncated forwards if the value is in a matching value
+func (vf *ValueTypeFilter) VisitTruncated(kind reflect.Kind, remaining int) {
+	vf.forward(func(va *ValueVisitorAdapter) {
+		va.VisitTruncated(kind, remaining)
+	})
+}
+
+// VisitPrePtrAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPrePtrAction(v reflect.Value) WalkAction {
+	return vf.pre(v, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPrePtrAction(v)
+	})
+}
+
+// VisitPreInterfaceAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPreInterfaceAction(v reflect.Value) WalkAction {
+	return vf.pre(v, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreInterfaceAction(v)
+	})
+}
+
+// VisitPreArrayAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPreArrayAction(length int, v reflect.Value) WalkAction {
+	return vf.pre(v, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreArrayAction(length, v)
+	})
+}
+
+// VisitPreSliceAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPreSliceAction(length int, v reflect.Value) WalkAction {
+	return vf.pre(v, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreSliceAction(length, v)
+	})
+}
+
+// VisitPreMapAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPreMapAction(length int, m reflect.Value) WalkAction {
+	return vf.pre(m, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreMapAction(length, m)
+	})
+}
+
+// VisitPreStructAction forwards if the value matches or is in a matching value, returning the action of the visitor.
+// Otherwise, Continue is returned to search the components of the value for matching values.
+func (vf *ValueTypeFilter) VisitPreStructAction(length int, v reflect.Value) WalkAction {
+	return vf.pre(v, func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreStructAction(length, v)
+	})
+}
@@ -0,0 +1,165 @@
+package goreflect
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type filterTestMoney struct {
+	Amount   int
+	Currency string
+}
+
+type filterTestCelsius int
+
+// filterTestCollector records the path of each value it visits
+type filterTestCollector struct {
+	path   *ValuePath
+	visits []string
+}
+
+func (c *filterTestCollector) Init() {
+	c.visits = nil
+}
+
+func (c *filterTestCollector) InitPath(path *ValuePath) {
+	c.path = path
+}
+
+func (c *filterTestCollector) VisitInt(i int) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitInt(%q, %d)", c.path, i))
+}
+
+func (c *filterTestCollector) VisitString(s string) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitString(%q, %s)", c.path, s))
+}
+
+func (c *filterTestCollector) VisitFunc(reflect.Value) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitFunc(%q)", c.path))
+}
+
+func (c *filterTestCollector) VisitPrePtr(reflect.Value) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitPrePtr(%q)", c.path))
+}
+
+func (c *filterTestCollector) VisitPostPtr(reflect.Value) {
+	c.visits = append(c.visits, fmt.Sprintf("VisitPostPtr(%q)", c.path))
+}
+
+func TestValueTypeFilter(t *testing.T) {
+	var (
+		c   = &filterTestCollector{}
+		w   = NewValueDepthFirstWalker(NewValueTypeFilter(NewTypeMatch(filterTestMoney{}, Ptr), c))
+		val = struct {
+			Name   string
+			Price  *filterTestMoney
+			Temps  []filterTestCelsius
+			Any    interface{}
+			Nested map[string]*filterTestMoney
+		}{
+			Name:   "foo",
+			Price:  &filterTestMoney{Amount: 5, Currency: "CAD"},
+			Temps:  []filterTestCelsius{20, 21},
+			Any:    &filterTestMoney{Amount: 6, Currency: "USD"},
+			Nested: map[string]*filterTestMoney{"bar": {Amount: 7, Currency: "EUR"}},
+		}
+	)
+
+	// Each matching value is visited as a whole, wherever it is, including inside an interface
+	w.Walk(val)
+	assert.Equal(
+		t,
+		[]string{
+			`VisitPrePtr(".Price")`,
+			`VisitInt(".Price.Amount", 5)`,
+			`VisitString(".Price.Currency", CAD)`,
+			`VisitPostPtr(".Price")`,
+			`VisitPrePtr(".Any")`,
+			`VisitInt(".Any.Amount", 6)`,
+			`VisitString(".Any.Currency", USD)`,
+			`VisitPostPtr(".Any")`,
+			`VisitPrePtr(".Nested[\"bar\"]")`,
+			`VisitInt(".Nested[\"bar\"].Amount", 7)`,
+			`VisitString(".Nested[\"bar\"].Currency", EUR)`,
+			`VisitPostPtr(".Nested[\"bar\"]")`,
+		},
+		c.visits,
+	)
+
+	// Named scalar types are matched by their own type
+	w.WithVisitor(NewValueTypeFilter(NewTypeMatch(filterTestCelsius(0)), c))
+	w.Walk(val)
+	assert.Equal(
+		t,
+		[]string{
+			`VisitInt(".Temps[0]", 20)`,
+			`VisitInt(".Temps[1]", 21)`,
+		},
+		c.visits,
+	)
+
+	// A matching value inside a matching value is part of the enclosing value
+	w.WithVisitor(NewValueTypeFilter(NewTypeMatch(reflect.Struct, Value, Ptr), c))
+	w.Walk(&filterTestMoney{Amount: 8})
+	assert.Equal(
+		t,
+		[]string{
+			`VisitPrePtr("")`,
+			`VisitInt(".Amount", 8)`,
+			`VisitString(".Currency", )`,
+			`VisitPostPtr("")`,
+		},
+		c.visits,
+	)
+}
+
+func TestValueTypeFilterWalkActions(t *testing.T) {
+	var (
+		c = &walkerTestController{}
+		w = NewValueDepthFirstWalker(NewValueTypeFilter(NewTypeMatch(reflect.Slice), c))
+	)
+
+	// The actions of the visitor apply inside matching values, and values outside them are searched regardless
+	w.Walk(map[string][][]int{"a": {{1, 2}, {3, 4, 5}}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitInt(1)",
+			"VisitInt(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitPostSlice(2)",
+		},
+		c.visits,
+	)
+}
+
+func TestValueFuncFilter(t *testing.T) {
+	var (
+		c = &filterTestCollector{}
+		w = NewValueDepthFirstWalker(NewValueFuncFilter(NewFuncMatcher().WithParamType(0).WithReturnType(""), c))
+	)
+
+	w.Walk(struct {
+		F func(int) string
+		G func()
+		H []func(int) string
+		I int
+	}{
+		H: []func(int) string{nil},
+	})
+	assert.Equal(
+		t,
+		[]string{
+			`VisitFunc(".F")`,
+			`VisitFunc(".H[0]")`,
+		},
+		c.visits,
+	)
+}
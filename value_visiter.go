@@ -1,6 +1,7 @@
 package goreflect
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -9,6 +10,22 @@ type InitVisitor interface {
 	Init()
 }
 
+// PathAware receives the path of the value being visited at the start of each walk, after Init is called.
+// The path is updated in place as the walk proceeds:
+// - during the visits of a value, it is the path of that value
+// - during the index, key, value, and field visits of a component, it is the path of the containing value
+// Copy the path to retain it after the visit that it is examined in.
+type PathAware interface {
+	InitPath(path *ValuePath)
+}
+
+// ContextAware receives the context of the walk at the start of each walk, after Init is called.
+// Long running visitors can use it to stop early when the context is cancelled.
+// The context is context.Background() unless the walk was started with a context.
+type ContextAware interface {
+	InitContext(ctx context.Context)
+}
+
 // BoolVisitor visits bool values
 type BoolVisitor interface {
 	VisitBool(bool)
@@ -99,6 +116,11 @@ type FuncVisitor interface {
 	VisitFunc(reflect.Value)
 }
 
+// NilVisitor visits nil ptr and interface values, and the invalid value that results from walking a nil
+type NilVisitor interface {
+	VisitNil(reflect.Value)
+}
+
 // PrePtrVisitor previsits ptr values
 type PrePtrVisitor interface {
 	VisitPrePtr(reflect.Value)
@@ -109,6 +131,16 @@ type PostPtrVisitor interface {
 	VisitPostPtr(reflect.Value)
 }
 
+// PreInterfaceVisitor previsits non-nil interface values
+type PreInterfaceVisitor interface {
+	VisitPreInterface(reflect.Value)
+}
+
+// PostInterfaceVisitor postvisits non-nil interface values
+type PostInterfaceVisitor interface {
+	VisitPostInterface(reflect.Value)
+}
+
 // PreArrayVisitor previsits array values
 type PreArrayVisitor interface {
 	VisitPreArray(length int, v reflect.Value)
@@ -209,6 +241,18 @@ type PostStructVisitor interface {
 	VisitPostStruct(length int, v reflect.Value)
 }
 
+// BackReferenceVisitor visits values that refer back to a value that has already been walked.
+// The path is where the referred to value was first walked.
+type BackReferenceVisitor interface {
+	VisitBackReference(v reflect.Value, path ValuePath)
+}
+
+// TruncatedVisitor visits the components of a value that were not walked because a walker limit was reached.
+// The kind is that of the value, and remaining is the number of components not walked.
+type TruncatedVisitor interface {
+	VisitTruncated(kind reflect.Kind, remaining int)
+}
+
 // ValueVisitor combines all above interfaces into one
 type ValueVisitor interface {
 	InitVisitor
@@ -230,8 +274,11 @@ type ValueVisitor interface {
 	StringVisitor
 	ChanVisitor
 	FuncVisitor
+	NilVisitor
 	PrePtrVisitor
 	PostPtrVisitor
+	PreInterfaceVisitor
+	PostInterfaceVisitor
 	PreArrayVisitor
 	PreArrayIndexVisitor
 	PostArrayIndexVisitor
@@ -252,4 +299,58 @@ type ValueVisitor interface {
 	PreStructFieldValueVisitor
 	PostStructFieldValueVisitor
 	PostStructVisitor
+	BackReferenceVisitor
+	TruncatedVisitor
+}
+
+// WalkAction is an enum of ways a visitor can tell a walker to proceed after previsiting a value
+type WalkAction uint
+
+// Walk actions
+const (
+	Continue     WalkAction = iota // walk the components of the value
+	SkipChildren                   // do not walk the components of the value, but do postvisit it
+	Stop                           // stop the walk immediately, without any further visits
+)
+
+// PrePtrActionVisitor previsits ptr values, and decides how to proceed
+type PrePtrActionVisitor interface {
+	VisitPrePtrAction(reflect.Value) WalkAction
+}
+
+// PreInterfaceActionVisitor previsits non-nil interface values, and decides how to proceed
+type PreInterfaceActionVisitor interface {
+	VisitPreInterfaceAction(reflect.Value) WalkAction
+}
+
+// PreArrayActionVisitor previsits array values, and decides how to proceed
+type PreArrayActionVisitor interface {
+	VisitPreArrayAction(length int, v reflect.Value) WalkAction
+}
+
+// PreSliceActionVisitor previsits slice values, and decides how to proceed
+type PreSliceActionVisitor interface {
+	VisitPreSliceAction(length int, v reflect.Value) WalkAction
+}
+
+// PreMapActionVisitor previsits map values, and decides how to proceed
+type PreMapActionVisitor interface {
+	VisitPreMapAction(length int, m reflect.Value) WalkAction
+}
+
+// PreStructActionVisitor previsits struct values, and decides how to proceed
+type PreStructActionVisitor interface {
+	VisitPreStructAction(length int, v reflect.Value) WalkAction
+}
+
+// ValueWalkController combines all above action interfaces into one.
+// If the ValueVisitor given to a walker is also a ValueWalkController,
+// the walker calls the action methods instead of the corresponding ValueVisitor previsit methods.
+type ValueWalkController interface {
+	PrePtrActionVisitor
+	PreInterfaceActionVisitor
+	PreArrayActionVisitor
+	PreSliceActionVisitor
+	PreMapActionVisitor
+	PreStructActionVisitor
 }
@@ -126,6 +126,11 @@ func (vr ValueVisitorProxy) VisitFunc(v reflect.Value) {
 	vr.dispatcher("VisitFunc", []reflect.Value{v})
 }
 
+// VisitNil dispatches ("VisitNil", nil)
+func (vr ValueVisitorProxy) VisitNil(v reflect.Value) {
+	vr.dispatcher("VisitNil", []reflect.Value{v})
+}
+
 // VisitPrePtr dispatches ("VisitPrePtr", ptr)
 func (vr ValueVisitorProxy) VisitPrePtr(v reflect.Value) {
 	vr.dispatcher("VisitPrePtr", []reflect.Value{v})
@@ -136,6 +141,16 @@ func (vr ValueVisitorProxy) VisitPostPtr(v reflect.Value) {
 	vr.dispatcher("VisitPostPtr", []reflect.Value{v})
 }
 
+// VisitPreInterface dispatches ("VisitPreInterface", interface)
+func (vr ValueVisitorProxy) VisitPreInterface(v reflect.Value) {
+	vr.dispatcher("VisitPreInterface", []reflect.Value{v})
+}
+
+// VisitPostInterface dispatches ("VisitPostInterface", interface)
+func (vr ValueVisitorProxy) VisitPostInterface(v reflect.Value) {
+	vr.dispatcher("VisitPostInterface", []reflect.Value{v})
+}
+
 // VisitPreArray dispatches ("VisitPreArray", length, array)
 func (vr ValueVisitorProxy) VisitPreArray(length int, v reflect.Value) {
 	vr.dispatcher("VisitPreArray", []reflect.Value{reflect.ValueOf(length), v})
@@ -235,3 +250,13 @@ func (vr ValueVisitorProxy) VisitPostStructFieldValue(length int, index int, f r
 func (vr ValueVisitorProxy) VisitPostStruct(length int, v reflect.Value) {
 	vr.dispatcher("VisitPostStruct", []reflect.Value{reflect.ValueOf(length), v})
 }
+
+// VisitBackReference dispatches ("VisitBackReference", value, path)
+func (vr ValueVisitorProxy) VisitBackReference(v reflect.Value, path ValuePath) {
+	vr.dispatcher("VisitBackReference", []reflect.Value{v, reflect.ValueOf(path)})
+}
+
+// VisitTruncated dispatches ("VisitTruncated", kind, remaining)
+func (vr ValueVisitorProxy) VisitTruncated(kind reflect.Kind, remaining int) {
+	vr.dispatcher("VisitTruncated", []reflect.Value{reflect.ValueOf(kind), reflect.ValueOf(remaining)})
+}
@@ -1,14 +1,26 @@
 package goreflect
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
 
 // ValueVisitorAdapter composes any subset of interfaces defined in ValueVisitor into a full ValueVisitor implementation.
 // Unimplemented interfaces are filled in with empty implementations.
+//
+// For each interface, the given visitor may instead implement the corresponding error interface, whose method returns an error.
+// A non-nil error is raised as a panic, which ValueDepthFirstWalker.WalkE recovers and returns.
+//
+// ValueVisitorAdapter is also PathAware and ContextAware, and passes the path and context to the given visitor
+// if it is PathAware or ContextAware.
+//
+// ValueVisitorAdapter is also a ValueWalkController. Each action method calls the corresponding previsit method,
+// then the corresponding action method, and returns the resulting action (Continue if the action method is unimplemented).
 type ValueVisitorAdapter struct {
 	initVisitor                 func()
+	initPath                    func(*ValuePath)
+	initContext                 func(context.Context)
 	boolVisitor                 func(bool)
 	intVisitor                  func(int)
 	int8Visitor                 func(int8)
@@ -27,8 +39,11 @@ type ValueVisitorAdapter struct {
 	stringVisitor               func(string)
 	chanVisitor                 func(reflect.Value)
 	funcVisitor                 func(reflect.Value)
+	nilVisitor                  func(reflect.Value)
 	prePtrVisitor               func(reflect.Value)
 	postPtrVisitor              func(reflect.Value)
+	preInterfaceVisitor         func(reflect.Value)
+	postInterfaceVisitor        func(reflect.Value)
 	preArrayVisitor             func(int, reflect.Value)
 	preArrayIndexVisitor        func(int, int, reflect.Value)
 	postArrayIndexVisitor       func(int, int, reflect.Value)
@@ -49,6 +64,14 @@ type ValueVisitorAdapter struct {
 	preStructFieldValueVisitor  func(int, int, reflect.StructField, reflect.Value)
 	postStructFieldValueVisitor func(int, int, reflect.StructField, reflect.Value)
 	postStructVisitor           func(int, reflect.Value)
+	backReferenceVisitor        func(reflect.Value, ValuePath)
+	truncatedVisitor            func(reflect.Kind, int)
+	prePtrActionVisitor         func(reflect.Value) WalkAction
+	preInterfaceActionVisitor   func(reflect.Value) WalkAction
+	preArrayActionVisitor       func(int, reflect.Value) WalkAction
+	preSliceActionVisitor       func(int, reflect.Value) WalkAction
+	preMapActionVisitor         func(int, reflect.Value) WalkAction
+	preStructActionVisitor      func(int, reflect.Value) WalkAction
 }
 
 // NewValueVisitorAdapter constructs a ValueVisitorAdapter
@@ -74,206 +97,455 @@ func (va *ValueVisitorAdapter) WithVisitor(visitor interface{}) *ValueVisitorAda
 	va.initVisitor = func() {}
 	if initv, ok := visitor.(InitVisitor); ok {
 		va.initVisitor = initv.Init
+	} else if initev, ok := visitor.(InitErrorVisitor); ok {
+		va.initVisitor = func() {
+			panicOnError(initev.Init())
+		}
+	}
+
+	va.initPath = func(*ValuePath) {}
+	if pa, ok := visitor.(PathAware); ok {
+		va.initPath = pa.InitPath
+	}
+
+	va.initContext = func(context.Context) {}
+	if ca, ok := visitor.(ContextAware); ok {
+		va.initContext = ca.InitContext
 	}
 
 	va.boolVisitor = func(bool) {}
 	if bv, ok := visitor.(BoolVisitor); ok {
 		va.boolVisitor = bv.VisitBool
+	} else if bev, ok := visitor.(BoolErrorVisitor); ok {
+		va.boolVisitor = func(v bool) {
+			panicOnError(bev.VisitBool(v))
+		}
 	}
 
 	va.intVisitor = func(int) {}
 	if iv, ok := visitor.(IntVisitor); ok {
 		va.intVisitor = iv.VisitInt
+	} else if iev, ok := visitor.(IntErrorVisitor); ok {
+		va.intVisitor = func(v int) {
+			panicOnError(iev.VisitInt(v))
+		}
 	}
 
 	va.int8Visitor = func(int8) {}
 	if iv8, ok := visitor.(Int8Visitor); ok {
 		va.int8Visitor = iv8.VisitInt8
+	} else if iv8e, ok := visitor.(Int8ErrorVisitor); ok {
+		va.int8Visitor = func(v int8) {
+			panicOnError(iv8e.VisitInt8(v))
+		}
 	}
 
 	va.int16Visitor = func(int16) {}
 	if iv16, ok := visitor.(Int16Visitor); ok {
 		va.int16Visitor = iv16.VisitInt16
+	} else if iv16e, ok := visitor.(Int16ErrorVisitor); ok {
+		va.int16Visitor = func(v int16) {
+			panicOnError(iv16e.VisitInt16(v))
+		}
 	}
 
 	va.int32Visitor = func(int32) {}
 	if iv32, ok := visitor.(Int32Visitor); ok {
 		va.int32Visitor = iv32.VisitInt32
+	} else if iv32e, ok := visitor.(Int32ErrorVisitor); ok {
+		va.int32Visitor = func(v int32) {
+			panicOnError(iv32e.VisitInt32(v))
+		}
 	}
 
 	va.int64Visitor = func(int64) {}
 	if iv64, ok := visitor.(Int64Visitor); ok {
 		va.int64Visitor = iv64.VisitInt64
+	} else if iv64e, ok := visitor.(Int64ErrorVisitor); ok {
+		va.int64Visitor = func(v int64) {
+			panicOnError(iv64e.VisitInt64(v))
+		}
 	}
 
 	va.uintVisitor = func(uint) {}
 	if uiv, ok := visitor.(UintVisitor); ok {
 		va.uintVisitor = uiv.VisitUint
+	} else if uiev, ok := visitor.(UintErrorVisitor); ok {
+		va.uintVisitor = func(v uint) {
+			panicOnError(uiev.VisitUint(v))
+		}
 	}
 
 	va.uint8Visitor = func(uint8) {}
 	if uiv8, ok := visitor.(Uint8Visitor); ok {
 		va.uint8Visitor = uiv8.VisitUint8
+	} else if uiv8e, ok := visitor.(Uint8ErrorVisitor); ok {
+		va.uint8Visitor = func(v uint8) {
+			panicOnError(uiv8e.VisitUint8(v))
+		}
 	}
 
 	va.uint16Visitor = func(uint16) {}
 	if uiv16, ok := visitor.(Uint16Visitor); ok {
 		va.uint16Visitor = uiv16.VisitUint16
+	} else if uiv16e, ok := visitor.(Uint16ErrorVisitor); ok {
+		va.uint16Visitor = func(v uint16) {
+			panicOnError(uiv16e.VisitUint16(v))
+		}
 	}
 
 	va.uint32Visitor = func(uint32) {}
 	if uiv32, ok := visitor.(Uint32Visitor); ok {
 		va.uint32Visitor = uiv32.VisitUint32
+	} else if uiv32e, ok := visitor.(Uint32ErrorVisitor); ok {
+		va.uint32Visitor = func(v uint32) {
+			panicOnError(uiv32e.VisitUint32(v))
+		}
 	}
 
 	va.uint64Visitor = func(uint64) {}
 	if uiv64, ok := visitor.(Uint64Visitor); ok {
 		va.uint64Visitor = uiv64.VisitUint64
+	} else if uiv64e, ok := visitor.(Uint64ErrorVisitor); ok {
+		va.uint64Visitor = func(v uint64) {
+			panicOnError(uiv64e.VisitUint64(v))
+		}
 	}
 
 	va.float32Visitor = func(float32) {}
 	if f32v, ok := visitor.(Float32Visitor); ok {
 		va.float32Visitor = f32v.VisitFloat32
+	} else if f32ev, ok := visitor.(Float32ErrorVisitor); ok {
+		va.float32Visitor = func(v float32) {
+			panicOnError(f32ev.VisitFloat32(v))
+		}
 	}
 
 	va.float64Visitor = func(float64) {}
 	if f64v, ok := visitor.(Float64Visitor); ok {
 		va.float64Visitor = f64v.VisitFloat64
+	} else if f64ev, ok := visitor.(Float64ErrorVisitor); ok {
+		va.float64Visitor = func(v float64) {
+			panicOnError(f64ev.VisitFloat64(v))
+		}
 	}
 
 	va.complex64Visitor = func(complex64) {}
 	if c64v, ok := visitor.(Complex64Visitor); ok {
 		va.complex64Visitor = c64v.VisitComplex64
+	} else if c64ev, ok := visitor.(Complex64ErrorVisitor); ok {
+		va.complex64Visitor = func(v complex64) {
+			panicOnError(c64ev.VisitComplex64(v))
+		}
 	}
 
 	va.complex128Visitor = func(complex128) {}
 	if c128v, ok := visitor.(Complex128Visitor); ok {
 		va.complex128Visitor = c128v.VisitComplex128
+	} else if c128ev, ok := visitor.(Complex128ErrorVisitor); ok {
+		va.complex128Visitor = func(v complex128) {
+			panicOnError(c128ev.VisitComplex128(v))
+		}
 	}
 
 	va.stringVisitor = func(string) {}
 	if sv, ok := visitor.(StringVisitor); ok {
 		va.stringVisitor = sv.VisitString
+	} else if sev, ok := visitor.(StringErrorVisitor); ok {
+		va.stringVisitor = func(v string) {
+			panicOnError(sev.VisitString(v))
+		}
 	}
 
 	va.chanVisitor = func(reflect.Value) {}
 	if chanv, ok := visitor.(ChanVisitor); ok {
 		va.chanVisitor = chanv.VisitChan
+	} else if chanev, ok := visitor.(ChanErrorVisitor); ok {
+		va.chanVisitor = func(v reflect.Value) {
+			panicOnError(chanev.VisitChan(v))
+		}
 	}
 
 	va.funcVisitor = func(reflect.Value) {}
 	if funcv, ok := visitor.(FuncVisitor); ok {
 		va.funcVisitor = funcv.VisitFunc
+	} else if funcev, ok := visitor.(FuncErrorVisitor); ok {
+		va.funcVisitor = func(v reflect.Value) {
+			panicOnError(funcev.VisitFunc(v))
+		}
+	}
+
+	va.nilVisitor = func(reflect.Value) {}
+	if nilv, ok := visitor.(NilVisitor); ok {
+		va.nilVisitor = nilv.VisitNil
+	} else if nilev, ok := visitor.(NilErrorVisitor); ok {
+		va.nilVisitor = func(v reflect.Value) {
+			panicOnError(nilev.VisitNil(v))
+		}
 	}
 
 	va.prePtrVisitor = func(reflect.Value) {}
 	if prePtrv, ok := visitor.(PrePtrVisitor); ok {
 		va.prePtrVisitor = prePtrv.VisitPrePtr
+	} else if prePtrev, ok := visitor.(PrePtrErrorVisitor); ok {
+		va.prePtrVisitor = func(v reflect.Value) {
+			panicOnError(prePtrev.VisitPrePtr(v))
+		}
 	}
 
 	va.postPtrVisitor = func(reflect.Value) {}
 	if postPtrv, ok := visitor.(PostPtrVisitor); ok {
 		va.postPtrVisitor = postPtrv.VisitPostPtr
+	} else if postPtrev, ok := visitor.(PostPtrErrorVisitor); ok {
+		va.postPtrVisitor = func(v reflect.Value) {
+			panicOnError(postPtrev.VisitPostPtr(v))
+		}
+	}
+
+	va.preInterfaceVisitor = func(reflect.Value) {}
+	if preInterfacev, ok := visitor.(PreInterfaceVisitor); ok {
+		va.preInterfaceVisitor = preInterfacev.VisitPreInterface
+	} else if preInterfaceev, ok := visitor.(PreInterfaceErrorVisitor); ok {
+		va.preInterfaceVisitor = func(v reflect.Value) {
+			panicOnError(preInterfaceev.VisitPreInterface(v))
+		}
+	}
+
+	va.postInterfaceVisitor = func(reflect.Value) {}
+	if postInterfacev, ok := visitor.(PostInterfaceVisitor); ok {
+		va.postInterfaceVisitor = postInterfacev.VisitPostInterface
+	} else if postInterfaceev, ok := visitor.(PostInterfaceErrorVisitor); ok {
+		va.postInterfaceVisitor = func(v reflect.Value) {
+			panicOnError(postInterfaceev.VisitPostInterface(v))
+		}
 	}
 
 	va.preArrayVisitor = func(int, reflect.Value) {}
 	if preArrayv, ok := visitor.(PreArrayVisitor); ok {
 		va.preArrayVisitor = preArrayv.VisitPreArray
+	} else if preArrayev, ok := visitor.(PreArrayErrorVisitor); ok {
+		va.preArrayVisitor = func(length int, v reflect.Value) {
+			panicOnError(preArrayev.VisitPreArray(length, v))
+		}
 	}
 
 	va.preArrayIndexVisitor = func(int, int, reflect.Value) {}
 	if preArrayIndexv, ok := visitor.(PreArrayIndexVisitor); ok {
 		va.preArrayIndexVisitor = preArrayIndexv.VisitPreArrayIndex
+	} else if preArrayIndexev, ok := visitor.(PreArrayIndexErrorVisitor); ok {
+		va.preArrayIndexVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(preArrayIndexev.VisitPreArrayIndex(length, index, v))
+		}
 	}
 
 	va.postArrayIndexVisitor = func(int, int, reflect.Value) {}
 	if postArrayIndexv, ok := visitor.(PostArrayIndexVisitor); ok {
 		va.postArrayIndexVisitor = postArrayIndexv.VisitPostArrayIndex
+	} else if postArrayIndexev, ok := visitor.(PostArrayIndexErrorVisitor); ok {
+		va.postArrayIndexVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(postArrayIndexev.VisitPostArrayIndex(length, index, v))
+		}
 	}
 
 	va.postArrayVisitor = func(int, reflect.Value) {}
 	if postArrayv, ok := visitor.(PostArrayVisitor); ok {
 		va.postArrayVisitor = postArrayv.VisitPostArray
+	} else if postArrayev, ok := visitor.(PostArrayErrorVisitor); ok {
+		va.postArrayVisitor = func(length int, v reflect.Value) {
+			panicOnError(postArrayev.VisitPostArray(length, v))
+		}
 	}
 
 	va.preSliceVisitor = func(int, reflect.Value) {}
 	if preSlicev, ok := visitor.(PreSliceVisitor); ok {
 		va.preSliceVisitor = preSlicev.VisitPreSlice
+	} else if preSliceev, ok := visitor.(PreSliceErrorVisitor); ok {
+		va.preSliceVisitor = func(length int, v reflect.Value) {
+			panicOnError(preSliceev.VisitPreSlice(length, v))
+		}
 	}
 
 	va.preSliceIndexVisitor = func(int, int, reflect.Value) {}
 	if preSliceIndexv, ok := visitor.(PreSliceIndexVisitor); ok {
 		va.preSliceIndexVisitor = preSliceIndexv.VisitPreSliceIndex
+	} else if preSliceIndexev, ok := visitor.(PreSliceIndexErrorVisitor); ok {
+		va.preSliceIndexVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(preSliceIndexev.VisitPreSliceIndex(length, index, v))
+		}
 	}
 
 	va.postSliceIndexVisitor = func(int, int, reflect.Value) {}
 	if postSliceIndexv, ok := visitor.(PostSliceIndexVisitor); ok {
 		va.postSliceIndexVisitor = postSliceIndexv.VisitPostSliceIndex
+	} else if postSliceIndexev, ok := visitor.(PostSliceIndexErrorVisitor); ok {
+		va.postSliceIndexVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(postSliceIndexev.VisitPostSliceIndex(length, index, v))
+		}
 	}
 
 	va.postSliceVisitor = func(int, reflect.Value) {}
 	if postSlicev, ok := visitor.(PostSliceVisitor); ok {
 		va.postSliceVisitor = postSlicev.VisitPostSlice
+	} else if postSliceev, ok := visitor.(PostSliceErrorVisitor); ok {
+		va.postSliceVisitor = func(length int, v reflect.Value) {
+			panicOnError(postSliceev.VisitPostSlice(length, v))
+		}
 	}
 
 	va.preMapVisitor = func(int, reflect.Value) {}
 	if preMapv, ok := visitor.(PreMapVisitor); ok {
 		va.preMapVisitor = preMapv.VisitPreMap
+	} else if preMapev, ok := visitor.(PreMapErrorVisitor); ok {
+		va.preMapVisitor = func(length int, m reflect.Value) {
+			panicOnError(preMapev.VisitPreMap(length, m))
+		}
 	}
 
 	va.preMapKeyValueVisitor = func(int, int, reflect.Value, reflect.Value) {}
 	if preMapkv, ok := visitor.(PreMapKeyValueVisitor); ok {
 		va.preMapKeyValueVisitor = preMapkv.VisitPreMapKeyValue
+	} else if preMapkev, ok := visitor.(PreMapKeyValueErrorVisitor); ok {
+		va.preMapKeyValueVisitor = func(length int, index int, k reflect.Value, v reflect.Value) {
+			panicOnError(preMapkev.VisitPreMapKeyValue(length, index, k, v))
+		}
 	}
 
 	va.preMapKeyVisitor = func(int, int, reflect.Value) {}
 	if preMapk, ok := visitor.(PreMapKeyVisitor); ok {
 		va.preMapKeyVisitor = preMapk.VisitPreMapKey
+	} else if preMapke, ok := visitor.(PreMapKeyErrorVisitor); ok {
+		va.preMapKeyVisitor = func(length int, index int, k reflect.Value) {
+			panicOnError(preMapke.VisitPreMapKey(length, index, k))
+		}
 	}
 
 	va.postMapKeyVisitor = func(int, int, reflect.Value) {}
 	if postMapk, ok := visitor.(PostMapKeyVisitor); ok {
 		va.postMapKeyVisitor = postMapk.VisitPostMapKey
+	} else if postMapke, ok := visitor.(PostMapKeyErrorVisitor); ok {
+		va.postMapKeyVisitor = func(length int, index int, k reflect.Value) {
+			panicOnError(postMapke.VisitPostMapKey(length, index, k))
+		}
 	}
 
 	va.preMapValueVisitor = func(int, int, reflect.Value) {}
 	if preMapv, ok := visitor.(PreMapValueVisitor); ok {
 		va.preMapValueVisitor = preMapv.VisitPreMapValue
+	} else if preMapev, ok := visitor.(PreMapValueErrorVisitor); ok {
+		va.preMapValueVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(preMapev.VisitPreMapValue(length, index, v))
+		}
 	}
 
 	va.postMapValueVisitor = func(int, int, reflect.Value) {}
 	if postMapv, ok := visitor.(PostMapValueVisitor); ok {
 		va.postMapValueVisitor = postMapv.VisitPostMapValue
+	} else if postMapev, ok := visitor.(PostMapValueErrorVisitor); ok {
+		va.postMapValueVisitor = func(length int, index int, v reflect.Value) {
+			panicOnError(postMapev.VisitPostMapValue(length, index, v))
+		}
 	}
 
 	va.postMapKeyValueVisitor = func(int, int, reflect.Value, reflect.Value) {}
 	if postMapkv, ok := visitor.(PostMapKeyValueVisitor); ok {
 		va.postMapKeyValueVisitor = postMapkv.VisitPostMapKeyValue
+	} else if postMapkev, ok := visitor.(PostMapKeyValueErrorVisitor); ok {
+		va.postMapKeyValueVisitor = func(length int, index int, k reflect.Value, v reflect.Value) {
+			panicOnError(postMapkev.VisitPostMapKeyValue(length, index, k, v))
+		}
 	}
 
 	va.postMapVisitor = func(int, reflect.Value) {}
 	if postMapv, ok := visitor.(PostMapVisitor); ok {
 		va.postMapVisitor = postMapv.VisitPostMap
+	} else if postMapev, ok := visitor.(PostMapErrorVisitor); ok {
+		va.postMapVisitor = func(length int, m reflect.Value) {
+			panicOnError(postMapev.VisitPostMap(length, m))
+		}
 	}
 
 	va.preStructVisitor = func(int, reflect.Value) {}
 	if preStructv, ok := visitor.(PreStructVisitor); ok {
 		va.preStructVisitor = preStructv.VisitPreStruct
+	} else if preStructev, ok := visitor.(PreStructErrorVisitor); ok {
+		va.preStructVisitor = func(length int, v reflect.Value) {
+			panicOnError(preStructev.VisitPreStruct(length, v))
+		}
 	}
 
 	va.preStructFieldValueVisitor = func(int, int, reflect.StructField, reflect.Value) {}
 	if preStructfv, ok := visitor.(PreStructFieldValueVisitor); ok {
 		va.preStructFieldValueVisitor = preStructfv.VisitPreStructFieldValue
+	} else if preStructfev, ok := visitor.(PreStructFieldValueErrorVisitor); ok {
+		va.preStructFieldValueVisitor = func(length int, index int, f reflect.StructField, v reflect.Value) {
+			panicOnError(preStructfev.VisitPreStructFieldValue(length, index, f, v))
+		}
 	}
 
 	va.postStructFieldValueVisitor = func(int, int, reflect.StructField, reflect.Value) {}
 	if postStructfv, ok := visitor.(PostStructFieldValueVisitor); ok {
 		va.postStructFieldValueVisitor = postStructfv.VisitPostStructFieldValue
+	} else if postStructfev, ok := visitor.(PostStructFieldValueErrorVisitor); ok {
+		va.postStructFieldValueVisitor = func(length int, index int, f reflect.StructField, v reflect.Value) {
+			panicOnError(postStructfev.VisitPostStructFieldValue(length, index, f, v))
+		}
 	}
 
 	va.postStructVisitor = func(int, reflect.Value) {}
 	if postStructv, ok := visitor.(PostStructVisitor); ok {
 		va.postStructVisitor = postStructv.VisitPostStruct
+	} else if postStructev, ok := visitor.(PostStructErrorVisitor); ok {
+		va.postStructVisitor = func(length int, v reflect.Value) {
+			panicOnError(postStructev.VisitPostStruct(length, v))
+		}
+	}
+
+	va.backReferenceVisitor = func(reflect.Value, ValuePath) {}
+	if backRefv, ok := visitor.(BackReferenceVisitor); ok {
+		va.backReferenceVisitor = backRefv.VisitBackReference
+	} else if backRefev, ok := visitor.(BackReferenceErrorVisitor); ok {
+		va.backReferenceVisitor = func(v reflect.Value, path ValuePath) {
+			panicOnError(backRefev.VisitBackReference(v, path))
+		}
+	}
+
+	va.truncatedVisitor = func(reflect.Kind, int) {}
+	if truncatedv, ok := visitor.(TruncatedVisitor); ok {
+		va.truncatedVisitor = truncatedv.VisitTruncated
+	} else if truncatedev, ok := visitor.(TruncatedErrorVisitor); ok {
+		va.truncatedVisitor = func(kind reflect.Kind, remaining int) {
+			panicOnError(truncatedev.VisitTruncated(kind, remaining))
+		}
+	}
+
+	va.prePtrActionVisitor = func(reflect.Value) WalkAction { return Continue }
+	if prePtrav, ok := visitor.(PrePtrActionVisitor); ok {
+		va.prePtrActionVisitor = prePtrav.VisitPrePtrAction
+	}
+
+	va.preInterfaceActionVisitor = func(reflect.Value) WalkAction { return Continue }
+	if preInterfaceav, ok := visitor.(PreInterfaceActionVisitor); ok {
+		va.preInterfaceActionVisitor = preInterfaceav.VisitPreInterfaceAction
+	}
+
+	va.preArrayActionVisitor = func(int, reflect.Value) WalkAction { return Continue }
+	if preArrayav, ok := visitor.(PreArrayActionVisitor); ok {
+		va.preArrayActionVisitor = preArrayav.VisitPreArrayAction
+	}
+
+	va.preSliceActionVisitor = func(int, reflect.Value) WalkAction { return Continue }
+	if preSliceav, ok := visitor.(PreSliceActionVisitor); ok {
+		va.preSliceActionVisitor = preSliceav.VisitPreSliceAction
+	}
+
+	va.preMapActionVisitor = func(int, reflect.Value) WalkAction { return Continue }
+	if preMapav, ok := visitor.(PreMapActionVisitor); ok {
+		va.preMapActionVisitor = preMapav.VisitPreMapAction
+	}
+
+	va.preStructActionVisitor = func(int, reflect.Value) WalkAction { return Continue }
+	if preStructav, ok := visitor.(PreStructActionVisitor); ok {
+		va.preStructActionVisitor = preStructav.VisitPreStructAction
 	}
 
 	return va
@@ -284,6 +556,16 @@ func (va ValueVisitorAdapter) Init() {
 	va.initVisitor()
 }
 
+// InitContext delegates to given ContextAware
+func (va ValueVisitorAdapter) InitContext(ctx context.Context) {
+	va.initContext(ctx)
+}
+
+// InitPath delegates to given PathAware
+func (va ValueVisitorAdapter) InitPath(path *ValuePath) {
+	va.initPath(path)
+}
+
 // VisitBool delegates to composed BoolVisitor
 func (va ValueVisitorAdapter) VisitBool(v bool) {
 	va.boolVisitor(v)
@@ -374,6 +656,11 @@ func (va ValueVisitorAdapter) VisitFunc(v reflect.Value) {
 	va.funcVisitor(v)
 }
 
+// VisitNil delegates to composed NilVisitor
+func (va ValueVisitorAdapter) VisitNil(v reflect.Value) {
+	va.nilVisitor(v)
+}
+
 // VisitPrePtr delegates to composed PrePtrVisitor
 func (va ValueVisitorAdapter) VisitPrePtr(v reflect.Value) {
 	va.prePtrVisitor(v)
@@ -384,6 +671,16 @@ func (va ValueVisitorAdapter) VisitPostPtr(v reflect.Value) {
 	va.postPtrVisitor(v)
 }
 
+// VisitPreInterface delegates to composed PreInterfaceVisitor
+func (va ValueVisitorAdapter) VisitPreInterface(v reflect.Value) {
+	va.preInterfaceVisitor(v)
+}
+
+// VisitPostInterface delegates to composed PostInterfaceVisitor
+func (va ValueVisitorAdapter) VisitPostInterface(v reflect.Value) {
+	va.postInterfaceVisitor(v)
+}
+
 // VisitPreArray delegates to composed PreArrayVisitor
 func (va ValueVisitorAdapter) VisitPreArray(length int, v reflect.Value) {
 	va.preArrayVisitor(length, v)
@@ -483,3 +780,49 @@ func (va ValueVisitorAdapter) VisitPostStructFieldValue(length int, index int, f
 func (va ValueVisitorAdapter) VisitPostStruct(length int, v reflect.Value) {
 	va.postStructVisitor(length, v)
 }
+
+// VisitBackReference delegates to composed BackReferenceVisitor
+func (va ValueVisitorAdapter) VisitBackReference(v reflect.Value, path ValuePath) {
+	va.backReferenceVisitor(v, path)
+}
+
+// VisitTruncated delegates to composed TruncatedVisitor
+func (va ValueVisitorAdapter) VisitTruncated(kind reflect.Kind, remaining int) {
+	va.truncatedVisitor(kind, remaining)
+}
+
+// VisitPrePtrAction delegates to composed PrePtrVisitor, then composed PrePtrActionVisitor
+func (va ValueVisitorAdapter) VisitPrePtrAction(v reflect.Value) WalkAction {
+	va.prePtrVisitor(v)
+	return va.prePtrActionVisitor(v)
+}
+
+// VisitPreInterfaceAction delegates to composed PreInterfaceVisitor, then composed PreInterfaceActionVisitor
+func (va ValueVisitorAdapter) VisitPreInterfaceAction(v reflect.Value) WalkAction {
+	va.preInterfaceVisitor(v)
+	return va.preInterfaceActionVisitor(v)
+}
+
+// VisitPreArrayAction delegates to composed PreArrayVisitor, then composed PreArrayActionVisitor
+func (va ValueVisitorAdapter) VisitPreArrayAction(length int, v reflect.Value) WalkAction {
+	va.preArrayVisitor(length, v)
+	return va.preArrayActionVisitor(length, v)
+}
+
+// VisitPreSliceAction delegates to composed PreSliceVisitor, then composed PreSliceActionVisitor
+func (va ValueVisitorAdapter) VisitPreSliceAction(length int, v reflect.Value) WalkAction {
+	va.preSliceVisitor(length, v)
+	return va.preSliceActionVisitor(length, v)
+}
+
+// VisitPreMapAction delegates to composed PreMapVisitor, then composed PreMapActionVisitor
+func (va ValueVisitorAdapter) VisitPreMapAction(length int, m reflect.Value) WalkAction {
+	va.preMapVisitor(length, m)
+	return va.preMapActionVisitor(length, m)
+}
+
+// VisitPreStructAction delegates to composed PreStructVisitor, then composed PreStructActionVisitor
+func (va ValueVisitorAdapter) VisitPreStructAction(length int, v reflect.Value) WalkAction {
+	va.preStructVisitor(length, v)
+	return va.preStructActionVisitor(length, v)
+}
@@ -0,0 +1,478 @@
+package goreflect
+
+import (
+	"context"
+	"reflect"
+)
+
+// ValueVisitorMux is a ValueVisitor that forwards every visit to several visitors in order, so that one walk can do
+// the work of several walks (eg, print, hash, and validate a value).
+// Each visitor can be any subset of ValueVisitor, as each one is adapted by a ValueVisitorAdapter.
+//
+// ValueVisitorMux is PathAware, ContextAware, and a ValueWalkController, and passes the path, context, and actions of
+// each visitor that is PathAware, ContextAware, or a ValueWalkController. The actions of the visitors are combined,
+// so that each visitor sees the walk it asked for:
+// - a visitor that skips the components of a value receives no visits until that value is postvisited
+// - a visitor that stops the walk receives no further visits, while the walk continues for the other visitors
+// - the components of a value are walked if any active visitor continues, and the walk stops when every visitor has stopped
+//
+// The combined actions rely on the components of a value being walked between its pre and post visits, as a
+// ValueDepthFirstWalker does. In a breadth first walk, a visitor that skips the components of a value that other
+// visitors continue with receives the visits of those components.
+type ValueVisitorMux struct {
+	visitors  []*ValueVisitorAdapter
+	depth     int
+	skippedAt []int
+	stopped   []bool
+}
+
+// NewValueVisitorMux constructs a ValueVisitorMux that forwards to the given visitors in order
+func NewValueVisitorMux(visitors ...interface{}) *ValueVisitorMux {
+	mux := &ValueVisitorMux{
+		visitors:  make([]*ValueVisitorAdapter, len(visitors)),
+		skippedAt: make([]int, len(visitors)),
+		stopped:   make([]bool, len(visitors)),
+	}
+
+	for i, visitor := range visitors {
+		mux.visitors[i] = NewValueVisitorAdapter(visitor)
+	}
+
+	return mux
+}
+
+// active returns true if visitor i has neither skipped the value being walked, nor stopped
+func (mux *ValueVisitorMux) active(i int) bool {
+	return (mux.skippedAt[i] == 0) && !mux.stopped[i]
+}
+
+// forward calls the given function with each active visitor
+func (mux *ValueVisitorMux) forward(visit func(va *ValueVisitorAdapter)) {
+	for i, va := range mux.visitors {
+		if mux.active(i) {
+			visit(va)
+		}
+	}
+}
+
+// pre calls the given action function with each active visitor, records which visitors skip the components or stop,
+// and returns the combined action
+func (mux *ValueVisitorMux) pre(action func(va *ValueVisitorAdapter) WalkAction) WalkAction {
+	mux.depth++
+
+	var walkChildren, anyActive bool
+	for i, va := range mux.visitors {
+		if !mux.active(i) {
+			if !mux.stopped[i] {
+				anyActive = true
+			}
+
+			continue
+		}
+
+		switch action(va) {
+		case Continue:
+			walkChildren, anyActive = true, true
+
+		case SkipChildren:
+			mux.skippedAt[i], anyActive = mux.depth, true
+
+		case Stop:
+			mux.stopped[i] = true
+		}
+	}
+
+	if walkChildren {
+		return Continue
+	}
+
+	if anyActive {
+		return SkipChildren
+	}
+
+	return Stop
+}
+
+// post calls the given function with each active visitor, and each visitor that skipped the components of the value
+// being postvisited, which become active again
+func (mux *ValueVisitorMux) post(visit func(va *ValueVisitorAdapter)) {
+	for i, va := range mux.visitors {
+		if mux.skippedAt[i] == mux.depth {
+			mux.skippedAt[i] = 0
+		}
+
+		if mux.active(i) {
+			visit(va)
+		}
+	}
+
+	mux.depth--
+}
+
+// Init resets the state of the mux, and forwards to each visitor
+func (mux *ValueVisitorMux) Init() {
+	mux.depth = 0
+	for i, va := range mux.visitors {
+		mux.skippedAt[i], mux.stopped[i] = 0, false
+		va.Init()
+	}
+}
+
+// InitPath forwards to each visitor
+func (mux *ValueVisitorMux) InitPath(path *ValuePath) {
+	for _, va := range mux.visitors {
+		va.InitPath(path)
+	}
+}
+
+// InitContext forwards to each visitor
+func (mux *ValueVisitorMux) InitContext(ctx context.Context) {
+	for _, va := range mux.visitors {
+		va.InitContext(ctx)
+	}
+}
+
+// VisitBool forwards to each active visitor
+func (mux *ValueVisitorMux) VisitBool(v bool) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitBool(v)
+	})
+}
+
+// VisitInt forwards to each active visitor
+func (mux *ValueVisitorMux) VisitInt(v int) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitInt(v)
+	})
+}
+
+// VisitInt8 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitInt8(v int8) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitInt8(v)
+	})
+}
+
+// VisitInt16 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitInt16(v int16) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitInt16(v)
+	})
+}
+
+// VisitInt32 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitInt32(v int32) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitInt32(v)
+	})
+}
+
+// VisitInt64 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitInt64(v int64) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitInt64(v)
+	})
+}
+
+// VisitUint forwards to each active visitor
+func (mux *ValueVisitorMux) VisitUint(v uint) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitUint(v)
+	})
+}
+
+// VisitUint8 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitUint8(v uint8) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitUint8(v)
+	})
+}
+
+// VisitUint16 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitUint16(v uint16) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitUint16(v)
+	})
+}
+
+// VisitUint32 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitUint32(v uint32) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitUint32(v)
+	})
+}
+
+// VisitUint64 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitUint64(v uint64) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitUint64(v)
+	})
+}
+
+// VisitFloat32 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitFloat32(v float32) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitFloat32(v)
+	})
+}
+
+// VisitFloat64 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitFloat64(v float64) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitFloat64(v)
+	})
+}
+
+// VisitComplex64 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitComplex64(v complex64) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitComplex64(v)
+	})
+}
+
+// VisitComplex128 forwards to each active visitor
+func (mux *ValueVisitorMux) VisitComplex128(v complex128) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitComplex128(v)
+	})
+}
+
+// VisitString forwards to each active visitor
+func (mux *ValueVisitorMux) VisitString(v string) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitString(v)
+	})
+}
+
+// VisitChan forwards to each active visitor
+func (mux *ValueVisitorMux) VisitChan(v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitChan(v)
+	})
+}
+
+// VisitFunc forwards to each active visitor
+func (mux *ValueVisitorMux) VisitFunc(v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitFunc(v)
+	})
+}
+
+// VisitNil forwards to each active visitor
+func (mux *ValueVisitorMux) VisitNil(v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitNil(v)
+	})
+}
+
+// VisitPrePtr forwards to each active visitor, as VisitPrePtrAction does
+func (mux *ValueVisitorMux) VisitPrePtr(v reflect.Value) {
+	mux.VisitPrePtrAction(v)
+}
+
+// VisitPostPtr forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostPtr(v reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostPtr(v)
+	})
+}
+
+// VisitPreInterface forwards to each active visitor, as VisitPreInterfaceAction does
+func (mux *ValueVisitorMux) VisitPreInterface(v reflect.Value) {
+	mux.VisitPreInterfaceAction(v)
+}
+
+// VisitPostInterface forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostInterface(v reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostInterface(v)
+	})
+}
+
+// VisitPreArray forwards to each active visitor, as VisitPreArrayAction does
+func (mux *ValueVisitorMux) VisitPreArray(length int, v reflect.Value) {
+	mux.VisitPreArrayAction(length, v)
+}
+
+// VisitPreArrayIndex forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreArrayIndex(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreArrayIndex(length, index, v)
+	})
+}
+
+// VisitPostArrayIndex forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostArrayIndex(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostArrayIndex(length, index, v)
+	})
+}
+
+// VisitPostArray forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostArray(length int, v reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostArray(length, v)
+	})
+}
+
+// VisitPreSlice forwards to each active visitor, as VisitPreSliceAction does
+func (mux *ValueVisitorMux) VisitPreSlice(length int, v reflect.Value) {
+	mux.VisitPreSliceAction(length, v)
+}
+
+// VisitPreSliceIndex forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreSliceIndex(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreSliceIndex(length, index, v)
+	})
+}
+
+// VisitPostSliceIndex forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostSliceIndex(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostSliceIndex(length, index, v)
+	})
+}
+
+// VisitPostSlice forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostSlice(length int, v reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostSlice(length, v)
+	})
+}
+
+// VisitPreMap forwards to each active visitor, as VisitPreMapAction does
+func (mux *ValueVisitorMux) VisitPreMap(length int, m reflect.Value) {
+	mux.VisitPreMapAction(length, m)
+}
+
+// VisitPreMapKeyValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreMapKeyValue(length, index, k, v)
+	})
+}
+
+// VisitPreMapKey forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreMapKey(length int, index int, k reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreMapKey(length, index, k)
+	})
+}
+
+// VisitPostMapKey forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostMapKey(length int, index int, k reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapKey(length, index, k)
+	})
+}
+
+// VisitPreMapValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreMapValue(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreMapValue(length, index, v)
+	})
+}
+
+// VisitPostMapValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostMapValue(length int, index int, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapValue(length, index, v)
+	})
+}
+
+// VisitPostMapKeyValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostMapKeyValue(length int, index int, k reflect.Value, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostMapKeyValue(length, index, k, v)
+	})
+}
+
+// VisitPostMap forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostMap(length int, m reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostMap(length, m)
+	})
+}
+
+// VisitPreStruct forwards to each active visitor, as VisitPreStructAction does
+func (mux *ValueVisitorMux) VisitPreStruct(length int, v reflect.Value) {
+	mux.VisitPreStructAction(length, v)
+}
+
+// VisitPreStructFieldValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPreStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPreStructFieldValue(length, index, f, v)
+	})
+}
+
+// VisitPostStructFieldValue forwards to each active visitor
+func (mux *ValueVisitorMux) VisitPostStructFieldValue(length int, index int, f reflect.StructField, v reflect.Value) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitPostStructFieldValue(length, index, f, v)
+	})
+}
+
+// VisitPostStruct forwards to each active visitor, and to each visitor that skipped the components of the value
+func (mux *ValueVisitorMux) VisitPostStruct(length int, v reflect.Value) {
+	mux.post(func(va *ValueVisitorAdapter) {
+		va.VisitPostStruct(length, v)
+	})
+}
+
+// VisitBackReference forwards to each active visitor
+func (mux *ValueVisitorMux) VisitBackReference(v reflect.Value, path ValuePath) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitBackReference(v, path)
+	})
+}
+
+// VisitTruncated forwards to each active visitor
+func (mux *ValueVisitorMux) VisitTruncated(kind reflect.Kind, remaining int) {
+	mux.forward(func(va *ValueVisitorAdapter) {
+		va.VisitTruncated(kind, remaining)
+	})
+}
+
+// VisitPrePtrAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPrePtrAction(v reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPrePtrAction(v)
+	})
+}
+
+// VisitPreInterfaceAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPreInterfaceAction(v reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreInterfaceAction(v)
+	})
+}
+
+// VisitPreArrayAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPreArrayAction(length int, v reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreArrayAction(length, v)
+	})
+}
+
+// VisitPreSliceAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPreSliceAction(length int, v reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreSliceAction(length, v)
+	})
+}
+
+// VisitPreMapAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPreMapAction(length int, m reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreMapAction(length, m)
+	})
+}
+
+// VisitPreStructAction forwards to each active visitor, and combines their actions
+func (mux *ValueVisitorMux) VisitPreStructAction(length int, v reflect.Value) WalkAction {
+	return mux.pre(func(va *ValueVisitorAdapter) WalkAction {
+		return va.VisitPreStructAction(length, v)
+	})
+}
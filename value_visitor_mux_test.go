@@ -0,0 +1,99 @@
+package goreflect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueVisitorMux(t *testing.T) {
+	var (
+		p = NewValuePrinter()
+		v = &walkerTestValidator{}
+		w = NewValueDepthFirstWalker(NewValueVisitorMux(p, v))
+	)
+
+	// Both visitors see the whole walk, including the path
+	w.Walk([]*walkerTestItem{{Name: "a"}, {Name: ""}})
+	assert.Equal(t, "[]*goreflect.walkerTestItem{&goreflect.walkerTestItem{Name: a, Price: nil}, &goreflect.walkerTestItem{Name: , Price: nil}}", p.Result())
+	assert.Equal(t, []string{"[0].Price", "[1].Name", "[1].Price"}, v.invalid)
+
+	// Visitors are reinitialized for each walk
+	w.Walk([]string{"", "b"})
+	assert.Equal(t, "[]string{, b}", p.Result())
+	assert.Equal(t, []string{"[0]"}, v.invalid)
+}
+
+func TestValueVisitorMuxWalkActions(t *testing.T) {
+	var (
+		c = &walkerTestController{}
+		p = NewValuePrinter()
+		w = NewValueDepthFirstWalker(NewValueVisitorMux(c, p))
+	)
+
+	// The controller skips the components of long slices, which are still walked for the printer
+	w.Walk([][]int{{1, 2}, {3, 4, 5}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSlice(2)",
+			"VisitInt(1)",
+			"VisitInt(2)",
+			"VisitPostSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitPostSlice(2)",
+		},
+		c.visits,
+	)
+	assert.Equal(t, "[][]int{[]int{1, 2}, []int{3, 4, 5}}", p.Result())
+
+	// The controller stops at the first map, and the walk continues for the printer
+	c.visits = nil
+	w.Walk([]interface{}{map[int]int{2: 3}, 1})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreMapAction",
+		},
+		c.visits,
+	)
+	assert.Equal(t, "[]interface {}{map[int]int{2: 3}, 1}", p.Result())
+
+	// Components are skipped when every visitor skips them, and the walk stops when every visitor stops
+	var (
+		c2 = &walkerTestController{}
+		w2 = NewValueDepthFirstWalker(NewValueVisitorMux(c, c2))
+	)
+
+	c.visits = nil
+	w2.Walk([][]int{{3, 4, 5}, {1}})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreSlice(3)",
+			"VisitPostSlice(3)",
+			"VisitPreSlice(1)",
+			"VisitInt(1)",
+			"VisitPostSlice(1)",
+			"VisitPostSlice(2)",
+		},
+		c.visits,
+	)
+	assert.Equal(t, c.visits, c2.visits)
+
+	c.visits, c2.visits = nil, nil
+	w2.Walk([]interface{}{map[int]int{2: 3}, 1})
+	assert.Equal(
+		t,
+		[]string{
+			"VisitPreSlice(2)",
+			"VisitPreMapAction",
+		},
+		c.visits,
+	)
+	assert.Equal(t, c.visits, c2.visits)
+}
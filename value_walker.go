@@ -0,0 +1,579 @@
+package goreflect
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BackReferenceMode is an enum of ways to handle a value that has already been walked
+type BackReferenceMode uint
+
+// Back reference modes
+const (
+	BackReferencePanic  BackReferenceMode = iota // panic with the path of the reference and the referred to value
+	BackReferenceSkip                            // silently skip the reference
+	BackReferenceNotify                          // call VisitBackReference with the path of the referred to value
+)
+
+// valueWalkerOptions contains the options common to all value walkers
+type valueWalkerOptions struct {
+	visitor           ValueVisitor
+	backReferenceMode BackReferenceMode
+	sharedReferences  bool
+	mapKeyLess        func(a, b reflect.Value) bool
+	exportedFields    bool
+	structTag         string
+	structFieldFilter func(reflect.StructField) bool
+	promotedFields    bool
+	maxDepth          int
+	maxElements       int
+	maxNodes          int
+}
+
+// WithVisitor sets the visitor to walk
+func (o *valueWalkerOptions) WithVisitor(visitor ValueVisitor) {
+	o.visitor = visitor
+}
+
+// WithStructFields has no effect, as struct fields are always walked.
+//
+// Deprecated: use WithExportedFieldsOnly, WithStructTag, or WithStructFieldFilter to select which fields are walked.
+func (o *valueWalkerOptions) WithStructFields() {}
+
+// WithoutStructFields has no effect, as struct fields are always walked.
+//
+// Deprecated: use WithExportedFieldsOnly, WithStructTag, or WithStructFieldFilter to select which fields are walked.
+func (o *valueWalkerOptions) WithoutStructFields() {}
+
+// WithExportedFieldsOnly sets the flag to walk only the exported fields of structs
+func (o *valueWalkerOptions) WithExportedFieldsOnly() {
+	o.exportedFields = true
+}
+
+// WithoutExportedFieldsOnly clears the flag to walk only the exported fields of structs
+func (o *valueWalkerOptions) WithoutExportedFieldsOnly() {
+	o.exportedFields = false
+}
+
+// WithStructTag sets the name of a struct tag that controls how struct fields are walked.
+// A tag value of "-" skips the field, any other non-empty value renames the field in the StructField passed to visitors
+// and in the path. Anything after a comma is ignored, so the tag can be shared with encodings like json.
+func (o *valueWalkerOptions) WithStructTag(tag string) {
+	o.structTag = tag
+}
+
+// WithoutStructTag clears the name of the struct tag that controls how struct fields are walked
+func (o *valueWalkerOptions) WithoutStructTag() {
+	o.structTag = ""
+}
+
+// WithStructFieldFilter sets a filter that returns true if a struct field should be walked.
+// The filter is passed the field before any renaming, with the full index of promoted fields.
+// A field rejected by the filter is not walked even if other field options select it.
+func (o *valueWalkerOptions) WithStructFieldFilter(filter func(reflect.StructField) bool) {
+	o.structFieldFilter = filter
+}
+
+// WithoutStructFieldFilter clears the filter of struct fields
+func (o *valueWalkerOptions) WithoutStructFieldFilter() {
+	o.structFieldFilter = nil
+}
+
+// WithPromotedFields sets the flag to walk the fields of embedded structs in place of the embedded struct,
+// as if they were fields of the embedding struct. Fields are promoted and shadowed as Go does at compile time:
+// a field shadows any field of the same name in a more deeply embedded struct, and fields of the same name at the same
+// depth are ambiguous and not walked. Shadowing applies to Go names, regardless of renaming by the struct tag.
+// An embedded struct that is renamed by the struct tag is walked as a regular field.
+func (o *valueWalkerOptions) WithPromotedFields() {
+	o.promotedFields = true
+}
+
+// WithoutPromotedFields clears the flag to walk the fields of embedded structs in place of the embedded struct
+func (o *valueWalkerOptions) WithoutPromotedFields() {
+	o.promotedFields = false
+}
+
+// WithMaxDepth sets the maximum depth of values to walk, where the top level value has a depth of 1.
+// The components of a value at the maximum depth are visited as truncated. A maximum of 0 means no limit.
+func (o *valueWalkerOptions) WithMaxDepth(depth int) {
+	o.maxDepth = depth
+}
+
+// WithoutMaxDepth clears the maximum depth of values to walk
+func (o *valueWalkerOptions) WithoutMaxDepth() {
+	o.maxDepth = 0
+}
+
+// WithMaxElements sets the maximum number of elements of each array, slice, or map to walk.
+// The remaining elements are visited as truncated. A maximum of 0 means no limit.
+func (o *valueWalkerOptions) WithMaxElements(elements int) {
+	o.maxElements = elements
+}
+
+// WithoutMaxElements clears the maximum number of elements of each array, slice, or map to walk
+func (o *valueWalkerOptions) WithoutMaxElements() {
+	o.maxElements = 0
+}
+
+// WithMaxNodes sets the maximum total number of values to walk, including the top level value.
+// Once the maximum is reached, the remaining components of every value being walked are visited as truncated.
+// A maximum of 0 means no limit.
+func (o *valueWalkerOptions) WithMaxNodes(nodes int) {
+	o.maxNodes = nodes
+}
+
+// WithoutMaxNodes clears the maximum total number of values to walk
+func (o *valueWalkerOptions) WithoutMaxNodes() {
+	o.maxNodes = 0
+}
+
+// WithBackReferenceMode sets the way to handle back references
+func (o *valueWalkerOptions) WithBackReferenceMode(mode BackReferenceMode) {
+	o.backReferenceMode = mode
+}
+
+// WithSharedReferences sets the flag to handle shared references as back references
+func (o *valueWalkerOptions) WithSharedReferences() {
+	o.sharedReferences = true
+}
+
+// WithoutSharedReferences clears the flag to handle shared references as back references
+func (o *valueWalkerOptions) WithoutSharedReferences() {
+	o.sharedReferences = false
+}
+
+// WithSortedMapKeys sets the map keys to be walked in the order of MapKeyNaturalLess
+func (o *valueWalkerOptions) WithSortedMapKeys() {
+	o.mapKeyLess = MapKeyNaturalLess
+}
+
+// WithMapKeyComparator sets the map keys to be walked in the order of the given less function
+func (o *valueWalkerOptions) WithMapKeyComparator(less func(a, b reflect.Value) bool) {
+	o.mapKeyLess = less
+}
+
+// WithoutSortedMapKeys sets the map keys to be walked in the order Go iterates them
+func (o *valueWalkerOptions) WithoutSortedMapKeys() {
+	o.mapKeyLess = nil
+}
+
+// backReference handles a back reference according to the back reference mode.
+// The path is where the referred to value was first walked, the walker is the name of the walker type for panic messages.
+func (o valueWalkerOptions) backReference(walker string, v reflect.Value, path ValuePath, st *valueWalkState) {
+	switch o.backReferenceMode {
+	case BackReferencePanic:
+		panic(fmt.Errorf("goreflect.%s.dispatch: value of type %s at %q refers back to %q", walker, v.Type(), st.path, path))
+
+	case BackReferenceNotify:
+		o.visitor.VisitBackReference(v, path.Copy())
+	}
+}
+
+// componentLimit returns how many of the n components of the value of the given kind at the current path
+// can be walked according to the depth and element limits
+func (o valueWalkerOptions) componentLimit(kind reflect.Kind, n int, st *valueWalkState) int {
+	if (o.maxDepth > 0) && (st.path.Len()+1 >= o.maxDepth) {
+		return 0
+	}
+
+	if (o.maxElements > 0) && (n > o.maxElements) && ((kind == reflect.Array) || (kind == reflect.Slice) || (kind == reflect.Map)) {
+		return o.maxElements
+	}
+
+	return n
+}
+
+// walkComponent returns true if component i of the n components of a value of the given kind should be walked,
+// given the limit returned by componentLimit. If a limit has been reached, the remaining components are visited as truncated.
+// Returns false if component i should not be walked, including when i = n.
+func (o valueWalkerOptions) walkComponent(kind reflect.Kind, i, limit, n int, st *valueWalkState) bool {
+	if i >= n {
+		return false
+	}
+
+	if (i < limit) && ((o.maxNodes == 0) || (st.nodes < o.maxNodes)) {
+		return true
+	}
+
+	st.visitor.VisitTruncated(kind, n-i)
+	return false
+}
+
+// walkedField is a struct field selected to be walked, and its value
+type walkedField struct {
+	field reflect.StructField
+	value reflect.Value
+}
+
+// fieldCandidate is a field of a struct or of a struct embedded in it, considered for walking
+type fieldCandidate struct {
+	field reflect.StructField
+	name  string
+	depth int
+	walk  bool
+}
+
+// structFields returns the fields of a struct that should be walked according to the field options, in order.
+// Fields renamed by the struct tag have the new name.
+// Promoted fields have the full index from the struct, and are omitted if they are reached through a nil pointer.
+func (o valueWalkerOptions) structFields(v reflect.Value) []walkedField {
+	var (
+		candidates = o.fieldCandidates(v.Type(), nil, 0, true, map[reflect.Type]bool{})
+		minDepth   = map[string]int{}
+		atMinDepth = map[string]int{}
+		fields     = make([]walkedField, 0, len(candidates))
+	)
+
+	// A name at the shallowest depth shadows the same name at deeper depths, and is ambiguous if it occurs more than once
+	for _, c := range candidates {
+		if depth, exists := minDepth[c.name]; !exists || (c.depth < depth) {
+			minDepth[c.name], atMinDepth[c.name] = c.depth, 1
+		} else if c.depth == depth {
+			atMinDepth[c.name]++
+		}
+	}
+
+	for _, c := range candidates {
+		if !c.walk || (c.depth > minDepth[c.name]) || (atMinDepth[c.name] > 1) {
+			continue
+		}
+
+		if fv, ok := fieldByIndex(v, c.field.Index); ok {
+			fields = append(fields, walkedField{field: c.field, value: fv})
+		}
+	}
+
+	return fields
+}
+
+// fieldCandidates returns the fields of the given struct type, and when promoted fields are on, the fields of embedded structs
+// in place of the embedded struct. Every field is returned under its Go name, so that shadowing can be applied as Go does,
+// and is marked to be walked if the field options select it. Embedded structs are marked as not walked when they are expanded.
+// The types map contains the embedded types being expanded, to stop a type that embeds a pointer to itself.
+func (o valueWalkerOptions) fieldCandidates(typ reflect.Type, index []int, depth int, walk bool, types map[reflect.Type]bool) []fieldCandidate {
+	var candidates []fieldCandidate
+
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		var (
+			sf        = typ.Field(i)
+			name      = sf.Name
+			walkField = walk
+			renamed   bool
+		)
+
+		sf.Index = append(append(make([]int, 0, len(index)+1), index...), i)
+
+		if o.structTag != "" {
+			if tag, ok := sf.Tag.Lookup(o.structTag); ok {
+				if comma := strings.IndexRune(tag, ','); comma >= 0 {
+					tag = tag[:comma]
+				}
+
+				if tag == "-" {
+					walkField = false
+				} else if tag != "" {
+					sf.Name, renamed = tag, true
+				}
+			}
+		}
+
+		if o.structFieldFilter != nil {
+			unrenamed := sf
+			unrenamed.Name = name
+			walkField = walkField && o.structFieldFilter(unrenamed)
+		}
+
+		if o.promotedFields && sf.Anonymous && !renamed {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+
+			if (et.Kind() == reflect.Struct) && !types[et] {
+				candidates = append(candidates, fieldCandidate{field: sf, name: name, depth: depth})
+
+				types[et] = true
+				candidates = append(candidates, o.fieldCandidates(et, sf.Index, depth+1, walkField, types)...)
+				delete(types, et)
+
+				continue
+			}
+		}
+
+		if o.exportedFields && (sf.PkgPath != "") {
+			walkField = false
+		}
+
+		candidates = append(candidates, fieldCandidate{field: sf, name: name, depth: depth, walk: walkField})
+	}
+
+	return candidates
+}
+
+// fieldByIndex returns the field of a struct with the given index, and true.
+// If the field is promoted through a nil embedded pointer, it returns false.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, false
+				}
+
+				v = v.Elem()
+			}
+		}
+
+		v = v.Field(idx)
+	}
+
+	return v, true
+}
+
+// mapEntry is a key and value of a map
+type mapEntry struct {
+	key   reflect.Value
+	value reflect.Value
+}
+
+// mapIter returns an iterator function for the keys and values of a map, in sorted order if a comparator has been set.
+// For each entry, the iterator returns (key, value, true).
+// After the last entry has been iterated, all further calls return (reflect.Value{}, reflect.Value{}, false).
+func (o valueWalkerOptions) mapIter(m reflect.Value) func() (reflect.Value, reflect.Value, bool) {
+	if o.mapKeyLess == nil {
+		iter := m.MapRange()
+
+		return func() (reflect.Value, reflect.Value, bool) {
+			if iter.Next() {
+				return iter.Key(), iter.Value(), true
+			}
+
+			return reflect.Value{}, reflect.Value{}, false
+		}
+	}
+
+	// The values are collected with the keys, as a key that is not equal to itself (NaN) cannot be used to index the map
+	var (
+		entries = make([]mapEntry, 0, m.Len())
+		iter    = m.MapRange()
+	)
+	for iter.Next() {
+		entries = append(entries, mapEntry{key: iter.Key(), value: iter.Value()})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return o.mapKeyLess(entries[i].key, entries[j].key)
+	})
+
+	i := 0
+	return func() (reflect.Value, reflect.Value, bool) {
+		if i < len(entries) {
+			e := entries[i]
+			i++
+			return e.key, e.value, true
+		}
+
+		return reflect.Value{}, reflect.Value{}, false
+	}
+}
+
+// MapKeyNaturalLess returns true if map key a sorts before map key b, as follows:
+// - bools, ints, uints, floats, and strings sort by value, with false before true, and NaN before all other floats
+// - interfaces sort by the values they contain
+// - all other keys, and keys of differing kinds, sort by their ValuePrinter string form
+func MapKeyNaturalLess(a, b reflect.Value) bool {
+	for (a.Kind() == reflect.Interface) && !a.IsNil() {
+		a = a.Elem()
+	}
+
+	for (b.Kind() == reflect.Interface) && !b.IsNil() {
+		b = b.Elem()
+	}
+
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Bool:
+			return !a.Bool() && b.Bool()
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+
+		case reflect.Float32, reflect.Float64:
+			fa, fb := a.Float(), b.Float()
+			if math.IsNaN(fa) || math.IsNaN(fb) {
+				return math.IsNaN(fa) && !math.IsNaN(fb)
+			}
+
+			return fa < fb
+
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+
+	return printedMapKey(a) < printedMapKey(b)
+}
+
+// printedMapKey returns the ValuePrinter string form of a map key.
+// Unlike fmt, ValuePrinter prints what pointers point to rather than their addresses, so the result is the same in every process.
+func printedMapKey(key reflect.Value) string {
+	p := NewValuePrinter().WithQuotedStrings()
+	w := NewValueDepthFirstWalker(NewValueVisitorAdapter(p))
+	w.WithBackReferenceMode(BackReferenceSkip)
+	w.Walk(key)
+
+	return p.Result()
+}
+
+// valueRef identifies a pointer, slice, or map by type and address.
+// Slices also need a length, since a slice and a shorter slice of it have the same type and address.
+type valueRef struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// refOf returns the valueRef of a pointer, slice, or map, and true if it can refer back to another value.
+// Other kinds, nil values, and empty slices cannot refer back to anything.
+func refOf(v reflect.Value) (valueRef, bool) {
+	if k := v.Kind(); ((k != reflect.Ptr) && (k != reflect.Slice) && (k != reflect.Map)) || v.IsNil() || ((v.Kind() == reflect.Slice) && (v.Len() == 0)) {
+		return valueRef{}, false
+	}
+
+	ref := valueRef{typ: v.Type(), ptr: v.Pointer()}
+	if v.Kind() == reflect.Slice {
+		ref.len = v.Len()
+	}
+
+	return ref, true
+}
+
+// walkedRef records the first path a pointer, slice, or map was walked at, and whether it is still being walked
+type walkedRef struct {
+	path    ValuePath
+	walking bool
+}
+
+// contextCheckInterval is the number of values walked between checks for cancellation of the walk context
+const contextCheckInterval = 1024
+
+// valueWalkState is the state of a single walk
+type valueWalkState struct {
+	visitor    ValueVisitor
+	controller ValueWalkController
+	ctx        context.Context
+	err        error
+	path       ValuePath
+	walked     map[valueRef]*walkedRef
+	stopped    bool
+	nodes      int
+}
+
+// newValueWalkState constructs the state for a walk with the given visitor
+func newValueWalkState(visitor ValueVisitor) *valueWalkState {
+	st := &valueWalkState{visitor: visitor, ctx: context.Background(), walked: map[valueRef]*walkedRef{}}
+	st.controller, _ = visitor.(ValueWalkController)
+
+	return st
+}
+
+// initVisitor initializes the visitor for the walk, including the path and context if it is PathAware or ContextAware
+func (st *valueWalkState) initVisitor() {
+	st.visitor.Init()
+
+	if pa, ok := st.visitor.(PathAware); ok {
+		pa.InitPath(&st.path)
+	}
+
+	if ca, ok := st.visitor.(ContextAware); ok {
+		ca.InitContext(st.ctx)
+	}
+}
+
+// cancelled returns true if the walk context has been cancelled, checking it only every contextCheckInterval values.
+// A cancelled context stops the walk, with the context error.
+func (st *valueWalkState) cancelled() bool {
+	if (st.nodes%contextCheckInterval == 1) && (st.ctx.Err() != nil) {
+		st.err = st.ctx.Err()
+		st.stopped = true
+	}
+
+	return st.stopped
+}
+
+// walkChildren returns true if the given action is to walk the components of a value.
+// A Stop action stops the walk.
+func (st *valueWalkState) walkChildren(action WalkAction) bool {
+	if action == Stop {
+		st.stopped = true
+	}
+
+	return action == Continue
+}
+
+// prePtr previsits a ptr, and returns true if the value it points to should be walked
+func (st *valueWalkState) prePtr(v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPrePtrAction(v))
+	}
+
+	st.visitor.VisitPrePtr(v)
+	return true
+}
+
+// preInterface previsits an interface, and returns true if the value it contains should be walked
+func (st *valueWalkState) preInterface(v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPreInterfaceAction(v))
+	}
+
+	st.visitor.VisitPreInterface(v)
+	return true
+}
+
+// preArray previsits an array, and returns true if the elements should be walked
+func (st *valueWalkState) preArray(n int, v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPreArrayAction(n, v))
+	}
+
+	st.visitor.VisitPreArray(n, v)
+	return true
+}
+
+// preSlice previsits a slice, and returns true if the elements should be walked
+func (st *valueWalkState) preSlice(n int, v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPreSliceAction(n, v))
+	}
+
+	st.visitor.VisitPreSlice(n, v)
+	return true
+}
+
+// preMap previsits a map, and returns true if the keys and values should be walked
+func (st *valueWalkState) preMap(n int, v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPreMapAction(n, v))
+	}
+
+	st.visitor.VisitPreMap(n, v)
+	return true
+}
+
+// preStruct previsits a struct, and returns true if the fields should be walked
+func (st *valueWalkState) preStruct(n int, v reflect.Value) bool {
+	if st.controller != nil {
+		return st.walkChildren(st.controller.VisitPreStructAction(n, v))
+	}
+
+	st.visitor.VisitPreStruct(n, v)
+	return true
+}